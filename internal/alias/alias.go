@@ -0,0 +1,149 @@
+// Package alias stores the command aliases and macros players define, in a
+// JSON file under the user config directory.
+//
+// An alias is a shorthand for a single command, optionally with some of its
+// arguments filled in. A macro is a sequence of command lines which may
+// refer to the arguments it was run with as $1, $2 and so on.
+package alias
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/roninii/pokedexcli/internal/paths"
+)
+
+type Store struct {
+	path    string
+	aliases map[string][]string
+	macros  map[string][]string
+}
+
+type storeFile struct {
+	Aliases map[string][]string `json:"aliases"`
+	Macros  map[string][]string `json:"macros"`
+}
+
+// Load reads the aliases file at path. A missing file gives an empty store.
+// An empty path keeps aliases in memory only.
+func Load(path string) (*Store, error) {
+	s := &Store{
+		path:    path,
+		aliases: map[string][]string{},
+		macros:  map[string][]string{},
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file storeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	for name, words := range file.Aliases {
+		s.aliases[name] = words
+	}
+	for name, lines := range file.Macros {
+		s.macros[name] = lines
+	}
+
+	return s, nil
+}
+
+// DefaultPath returns the aliases file location under the user config
+// directory.
+func DefaultPath() (string, error) {
+	configDir, err := paths.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "aliases.json"), nil
+}
+
+// Alias returns the command words that the alias name stands for.
+func (s *Store) Alias(name string) ([]string, bool) {
+	words, ok := s.aliases[name]
+	return words, ok
+}
+
+// Macro returns the command lines run by the macro name.
+func (s *Store) Macro(name string) ([]string, bool) {
+	lines, ok := s.macros[name]
+	return lines, ok
+}
+
+// Aliases returns the names of every alias, sorted.
+func (s *Store) Aliases() []string {
+	return sortedKeys(s.aliases)
+}
+
+// Macros returns the names of every macro, sorted.
+func (s *Store) Macros() []string {
+	return sortedKeys(s.macros)
+}
+
+// SetAlias defines or replaces the alias name, replacing any macro of the
+// same name, and saves the store.
+func (s *Store) SetAlias(name string, words []string) error {
+	delete(s.macros, name)
+	s.aliases[name] = words
+	return s.save()
+}
+
+// SetMacro defines or replaces the macro name, replacing any alias of the
+// same name, and saves the store.
+func (s *Store) SetMacro(name string, lines []string) error {
+	delete(s.aliases, name)
+	s.macros[name] = lines
+	return s.save()
+}
+
+// Remove deletes the alias or macro called name, reporting whether there
+// was one.
+func (s *Store) Remove(name string) (bool, error) {
+	_, isAlias := s.aliases[name]
+	_, isMacro := s.macros[name]
+	if !isAlias && !isMacro {
+		return false, nil
+	}
+
+	delete(s.aliases, name)
+	delete(s.macros, name)
+	return true, s.save()
+}
+
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(storeFile{Aliases: s.aliases, Macros: s.macros}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, append(data, '\n'), 0o644)
+}
+
+func sortedKeys(m map[string][]string) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
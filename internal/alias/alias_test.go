@@ -0,0 +1,54 @@
+package alias
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config", "aliases.json")
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Loading missing file: %v", err)
+	}
+	if err := s.SetAlias("cp", []string{"catch", "pikachu"}); err != nil {
+		t.Fatalf("Setting alias: %v", err)
+	}
+	if err := s.SetMacro("hunt", []string{"explore $1", "catch $2"}); err != nil {
+		t.Fatalf("Setting macro: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Reloading: %v", err)
+	}
+	if words, ok := loaded.Alias("cp"); !ok || !slices.Equal(words, []string{"catch", "pikachu"}) {
+		t.Errorf("Expected alias cp to survive a reload, got %v", words)
+	}
+	if lines, ok := loaded.Macro("hunt"); !ok || !slices.Equal(lines, []string{"explore $1", "catch $2"}) {
+		t.Errorf("Expected macro hunt to survive a reload, got %v", lines)
+	}
+}
+
+func TestSharedNames(t *testing.T) {
+	s, _ := Load("")
+	s.SetAlias("go", []string{"map"})
+	s.SetMacro("go", []string{"map", "map"})
+
+	if _, ok := s.Alias("go"); ok {
+		t.Error("Expected the macro to replace the alias of the same name")
+	}
+	if !slices.Equal(s.Macros(), []string{"go"}) {
+		t.Errorf("Expected one macro, got %v", s.Macros())
+	}
+
+	removed, err := s.Remove("go")
+	if err != nil || !removed {
+		t.Fatalf("Expected go to be removed: %v", err)
+	}
+	if removed, _ := s.Remove("go"); removed {
+		t.Error("Expected removing a missing name to report false")
+	}
+}
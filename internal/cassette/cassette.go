@@ -0,0 +1,206 @@
+// Package cassette records HTTP interactions to a file and replays them
+// later, so tests can exercise the real PokeAPI responses without network
+// access.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+type Mode int
+
+const (
+	// Replay serves responses from the cassette and fails any request it
+	// has no recording for.
+	Replay Mode = iota
+	// Record passes requests through to the real transport and appends
+	// every interaction to the cassette.
+	Record
+)
+
+func ParseMode(s string) (Mode, error) {
+	switch s {
+	case "replay":
+		return Replay, nil
+	case "record":
+		return Record, nil
+	}
+	return 0, fmt.Errorf("unknown cassette mode %q (expected \"record\" or \"replay\")", s)
+}
+
+var ErrUnexpectedRequest = errors.New("cassette: no recorded interaction for request")
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+type cassetteFile struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder is an http.RoundTripper that records to or replays from a
+// cassette file depending on its mode.
+type Recorder struct {
+	path string
+	mode Mode
+	next http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// New returns a Recorder for the cassette at path. In Replay mode the
+// cassette must already exist; in Record mode it is overwritten and requests
+// are sent using next, or http.DefaultTransport if next is nil.
+func New(path string, mode Mode, next http.RoundTripper) (*Recorder, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	r := &Recorder{path: path, mode: mode, next: next}
+
+	if mode == Replay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var file cassetteFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("decoding cassette %s: %w", path, err)
+		}
+		r.interactions = file.Interactions
+		r.used = make([]bool, len(file.Interactions))
+	}
+
+	return r, nil
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == Record {
+		return r.record(req)
+	}
+	return r.replay(req)
+}
+
+// Unused returns the recorded requests that have not been replayed, which
+// usually means the code under test changed what it fetches.
+func (r *Recorder) Unused() []Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []Request
+	for i, used := range r.used {
+		if !used {
+			unused = append(unused, r.interactions[i].Request)
+		}
+	}
+	return unused
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	res, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Request: Request{Method: req.Method, URL: req.URL.String()},
+		Response: Response{
+			StatusCode: res.StatusCode,
+			Header:     res.Header,
+			Body:       string(body),
+		},
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.interactions = append(r.interactions, interaction)
+	// Saving after every interaction keeps the cassette complete even if
+	// the session ends abruptly.
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+
+	return interaction.Response.toHTTP(req), nil
+}
+
+// replay serves the first unused interaction matching req, so repeated
+// requests for the same URL are answered in the order they were recorded.
+// Once every match has been used the last one is served again, so a
+// replay does not fail just because it repeats a request more often than
+// the recording did.
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := -1
+	for i, interaction := range r.interactions {
+		if interaction.Request.Method != req.Method || interaction.Request.URL != req.URL.String() {
+			continue
+		}
+		if !r.used[i] {
+			r.used[i] = true
+			return interaction.Response.toHTTP(req), nil
+		}
+		last = i
+	}
+	if last >= 0 {
+		return r.interactions[last].Response.toHTTP(req), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrUnexpectedRequest, req.Method, req.URL)
+}
+
+// save must be called with r.mu held.
+func (r *Recorder) save() error {
+	data, err := json.MarshalIndent(cassetteFile{Interactions: r.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, data, 0o644)
+}
+
+func (r Response) toHTTP(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(r.StatusCode) + " " + http.StatusText(r.StatusCode),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader([]byte(r.Body))),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,69 @@
+package cassette
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/roninii/pokedexcli/internal/pokeapi"
+	"github.com/roninii/pokedexcli/internal/pokeapitest"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	ctx := context.Background()
+
+	server := pokeapitest.NewServer()
+	recorder, err := New(path, Record, server.Client().Transport)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := pokeapi.NewClient(server.BaseURL(), &http.Client{Transport: recorder}, nil)
+	recorded, err := client.GetPokemon(ctx, "pikachu")
+	if err != nil {
+		t.Fatalf("Unexpected error recording: %v", err)
+	}
+	if _, err := client.GetPokemon(ctx, "missingno"); !errors.Is(err, pokeapi.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound while recording, got %v", err)
+	}
+	server.Close()
+
+	// The server is gone, so everything below must come from the cassette.
+	player, err := New(path, Replay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client = pokeapi.NewClient(server.BaseURL(), &http.Client{Transport: player}, nil)
+	replayed, err := client.GetPokemon(ctx, "pikachu")
+	if err != nil {
+		t.Fatalf("Unexpected error replaying: %v", err)
+	}
+	if replayed.ID != recorded.ID || replayed.Name != recorded.Name {
+		t.Errorf("Expected replayed %s #%d, got %s #%d", recorded.Name, recorded.ID, replayed.Name, replayed.ID)
+	}
+
+	if unused := player.Unused(); len(unused) != 1 {
+		t.Errorf("Expected the missingno request to be unused, got %v", unused)
+	}
+	if _, err := client.GetPokemon(ctx, "missingno"); !errors.Is(err, pokeapi.ErrNotFound) {
+		t.Errorf("Expected recorded 404 to replay as ErrNotFound, got %v", err)
+	}
+
+	again, err := client.GetPokemon(ctx, "pikachu")
+	if err != nil || again.ID != recorded.ID {
+		t.Errorf("Expected a repeated pikachu request to replay the recording, got %+v (%v)", again, err)
+	}
+	if _, err := client.GetPokemon(ctx, "mewtwo"); !errors.Is(err, ErrUnexpectedRequest) {
+		t.Errorf("Expected an unrecorded request to be unexpected, got %v", err)
+	}
+}
+
+func TestReplayMissingCassette(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), Replay, nil); err == nil {
+		t.Errorf("Expected an error replaying a missing cassette")
+	}
+}
@@ -0,0 +1,253 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxExpansionDepth bounds how many aliases and macros may expand through
+// one another, so that a macro which runs itself fails instead of looping.
+const maxExpansionDepth = 10
+
+var macroParam = regexp.MustCompile(`\$(\d+)`)
+
+// Lookup finds a command by its name or one of its built-in aliases.
+func Lookup(name string) (CliCommand, bool) {
+	if command, ok := Commands[name]; ok {
+		return command, true
+	}
+	for _, command := range Commands {
+		if slices.Contains(command.Aliases, name) {
+			return command, true
+		}
+	}
+	return CliCommand{}, false
+}
+
+// Expand resolves user-defined aliases and macros in words, returning the
+// commands to run in order. Input that names a command, or nothing known,
+// is returned unchanged as the only command.
+func (c *Config) Expand(words []string) ([][]string, error) {
+	return c.expand(words, 0)
+}
+
+func (c *Config) expand(words []string, depth int) ([][]string, error) {
+	if len(words) == 0 || c.Aliases == nil {
+		return [][]string{words}, nil
+	}
+	if _, ok := Lookup(words[0]); ok {
+		return [][]string{words}, nil
+	}
+	if depth >= maxExpansionDepth {
+		return nil, fmt.Errorf("Too many expansions of %s; does it run itself?", words[0])
+	}
+
+	if target, ok := c.Aliases.Alias(words[0]); ok {
+		return c.expand(append(slices.Clone(target), words[1:]...), depth+1)
+	}
+
+	lines, ok := c.Aliases.Macro(words[0])
+	if !ok {
+		return [][]string{words}, nil
+	}
+	var expanded [][]string
+	for _, line := range lines {
+		lineWords, err := Tokenize(line)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid line in macro %s: %v", words[0], err)
+		}
+		lineWords, err = substituteParams(words[0], lineWords, words[1:])
+		if err != nil {
+			return nil, err
+		}
+		commands, err := c.expand(lineWords, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		expanded = append(expanded, commands...)
+	}
+	return expanded, nil
+}
+
+// substituteParams replaces $1, $2 and so on in the words of a macro line
+// with the arguments the macro was run with.
+func substituteParams(macro string, words []string, args []string) ([]string, error) {
+	var err error
+	substituted := make([]string, len(words))
+	for i, word := range words {
+		substituted[i] = macroParam.ReplaceAllStringFunc(word, func(param string) string {
+			n, _ := strconv.Atoi(param[1:])
+			if n < 1 || n > len(args) {
+				err = fmt.Errorf("Macro %s needs argument %s", macro, param)
+				return param
+			}
+			return args[n-1]
+		})
+	}
+	return substituted, err
+}
+
+type aliasView struct {
+	Name    string `json:"name"`
+	Command string `json:"command"`
+	BuiltIn bool   `json:"builtin"`
+}
+
+type macroView struct {
+	Name     string   `json:"name"`
+	Commands []string `json:"commands"`
+}
+
+func CommandAlias(ctx context.Context, config *Config, args []string) error {
+	if config.Aliases == nil {
+		return fmt.Errorf("Aliases are not available")
+	}
+
+	switch len(args) {
+	case 0:
+		var views []aliasView
+		for _, name := range commandNames() {
+			for _, alias := range Commands[name].Aliases {
+				views = append(views, aliasView{Name: alias, Command: name, BuiltIn: true})
+			}
+		}
+		slices.SortFunc(views, func(a, b aliasView) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		for _, name := range config.Aliases.Aliases() {
+			words, _ := config.Aliases.Alias(name)
+			views = append(views, aliasView{Name: name, Command: strings.Join(words, " ")})
+		}
+
+		for _, view := range views {
+			err := config.Out.Emit(view, func(w io.Writer) {
+				fmt.Fprintf(w, "%s = %s\n", view.Name, view.Command)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case 1:
+		name := args[0]
+		view := aliasView{Name: name}
+		if words, ok := config.Aliases.Alias(name); ok {
+			view.Command = strings.Join(words, " ")
+		} else if command, ok := Lookup(name); ok && command.Name != name {
+			view.Command, view.BuiltIn = command.Name, true
+		} else {
+			return fmt.Errorf("No alias named %s", name)
+		}
+		return config.Out.Emit(view, func(w io.Writer) {
+			fmt.Fprintf(w, "%s = %s\n", view.Name, view.Command)
+		})
+	}
+
+	name, words := args[0], args[1:]
+	if err := checkAliasName(name); err != nil {
+		return err
+	}
+	if _, ok := Lookup(words[0]); !ok {
+		if _, ok := config.Aliases.Alias(words[0]); !ok {
+			if _, ok := config.Aliases.Macro(words[0]); !ok {
+				return fmt.Errorf("Unknown command: %s", words[0])
+			}
+		}
+	}
+	if err := config.Aliases.SetAlias(name, words); err != nil {
+		return fmt.Errorf("Error saving aliases: %v", err)
+	}
+
+	config.Out.Printf("%s = %s\n", name, strings.Join(words, " "))
+	return nil
+}
+
+func CommandMacro(ctx context.Context, config *Config, args []string) error {
+	if config.Aliases == nil {
+		return fmt.Errorf("Macros are not available")
+	}
+
+	if len(args) <= 1 {
+		names := config.Aliases.Macros()
+		if len(args) == 1 {
+			if _, ok := config.Aliases.Macro(args[0]); !ok {
+				return fmt.Errorf("No macro named %s", args[0])
+			}
+			names = args[:1]
+		}
+
+		for _, name := range names {
+			lines, _ := config.Aliases.Macro(name)
+			view := macroView{Name: name, Commands: lines}
+			err := config.Out.Emit(view, func(w io.Writer) {
+				fmt.Fprintf(w, "%s = %s\n", view.Name, strings.Join(view.Commands, "; "))
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	name, lines := args[0], args[1:]
+	if err := checkAliasName(name); err != nil {
+		return err
+	}
+	for _, line := range lines {
+		words, err := Tokenize(line)
+		if err != nil {
+			return fmt.Errorf("Invalid command %q: %v", line, err)
+		}
+		if len(words) == 0 {
+			return fmt.Errorf("Macros cannot contain empty commands")
+		}
+	}
+	if err := config.Aliases.SetMacro(name, lines); err != nil {
+		return fmt.Errorf("Error saving macros: %v", err)
+	}
+
+	config.Out.Printf("%s = %s\n", name, strings.Join(lines, "; "))
+	return nil
+}
+
+func CommandUnalias(ctx context.Context, config *Config, args []string) error {
+	if config.Aliases == nil {
+		return fmt.Errorf("Aliases are not available")
+	}
+
+	removed, err := config.Aliases.Remove(args[0])
+	if err != nil {
+		return fmt.Errorf("Error saving aliases: %v", err)
+	}
+	if !removed {
+		return fmt.Errorf("No alias or macro named %s", args[0])
+	}
+
+	config.Out.Printf("Removed %s\n", args[0])
+	return nil
+}
+
+// checkAliasName rejects names that would be shadowed by a command, since
+// commands are looked up before aliases.
+func checkAliasName(name string) error {
+	if command, ok := Lookup(name); ok {
+		return fmt.Errorf("%s is already a name for the %s command", name, command.Name)
+	}
+	if strings.HasPrefix(name, "!") || strings.HasPrefix(name, "-") {
+		return fmt.Errorf("Invalid alias name: %s", name)
+	}
+	return nil
+}
+
+// userAliases completes the names of aliases and macros the player defined.
+func userAliases(c *Config) []string {
+	if c.Aliases == nil {
+		return nil
+	}
+	return append(c.Aliases.Aliases(), c.Aliases.Macros()...)
+}
@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/roninii/pokedexcli/internal/output"
+)
+
+// Arg describes a positional argument accepted by a command. Arguments are
+// required unless marked Optional, and only the last one may be Variadic.
+type Arg struct {
+	Name string
+	// Description is shown by "help <command>".
+	Description string
+	Optional    bool
+	Variadic    bool
+	// Verbatim marks a Variadic argument whose words are passed on
+	// untouched, so an output flag among them belongs to the command line
+	// being stored, such as the body of an alias, rather than to this one.
+	Verbatim bool
+	// KeepCase marks an argument whose case matters, such as a nickname,
+	// so NormalizeCase leaves it alone.
+	KeepCase bool
+	// Complete lists the values offered for tab completion, if any.
+	Complete func(*Config) []string
+}
+
+func (a Arg) String() string {
+	s := "<" + a.Name + ">"
+	if a.Optional {
+		s = "[" + a.Name + "]"
+	}
+	if a.Variadic {
+		s += "..."
+	}
+	return s
+}
+
+// UsageError is returned by ValidateArgs when a command is called with the
+// wrong number of arguments.
+type UsageError struct {
+	Usage string
+}
+
+func (e *UsageError) Error() string {
+	return "usage: " + e.Usage
+}
+
+// Usage returns the command name followed by its arguments, for example
+// "catch <pokemon>".
+func (c CliCommand) Usage() string {
+	parts := []string{c.Name}
+	for _, arg := range c.Args {
+		parts = append(parts, arg.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+// ValidateArgs checks that args matches the command's declared arguments,
+// so callbacks can index required arguments without checking first. An
+// empty required argument, such as a quoted "", counts as missing.
+func (c CliCommand) ValidateArgs(args []string) error {
+	required := 0
+	variadic := false
+	for i, arg := range c.Args {
+		if !arg.Optional {
+			required++
+			if i < len(args) && args[i] == "" {
+				return &UsageError{Usage: c.Usage()}
+			}
+		}
+		variadic = variadic || arg.Variadic
+	}
+
+	if len(args) < required || (!variadic && len(args) > len(c.Args)) {
+		return &UsageError{Usage: c.Usage()}
+	}
+	return nil
+}
+
+// SplitOutputFlag removes a per-command "-o <format>" or "--output <format>"
+// flag from args, returning the remaining arguments and the selected
+// format, or def if no flag was given.
+func SplitOutputFlag(args []string, def output.Format) ([]string, output.Format, error) {
+	rest := []string{}
+	format := def
+
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		if !isOutputFlag(name) {
+			rest = append(rest, args[i])
+			continue
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, "", fmt.Errorf("%s requires a format", name)
+			}
+			i++
+			value = args[i]
+		}
+
+		f, err := output.ParseFormat(value)
+		if err != nil {
+			return nil, "", err
+		}
+		format = f
+	}
+
+	return rest, format, nil
+}
+
+// SplitOutputFlag is like the package-level SplitOutputFlag, except that
+// it stops looking for the flag where a Verbatim argument begins and passes
+// the rest of args through unchanged.
+func (c CliCommand) SplitOutputFlag(args []string, def output.Format) ([]string, output.Format, error) {
+	verbatim := slices.IndexFunc(c.Args, func(arg Arg) bool { return arg.Verbatim })
+	if verbatim < 0 {
+		return SplitOutputFlag(args, def)
+	}
+
+	positional := 0
+	for i := 0; i < len(args); i++ {
+		name, _, hasValue := strings.Cut(args[i], "=")
+		if isOutputFlag(name) {
+			if !hasValue {
+				i++
+			}
+			continue
+		}
+		if positional == verbatim {
+			rest, format, err := SplitOutputFlag(args[:i], def)
+			if err != nil {
+				return nil, "", err
+			}
+			return append(rest, args[i:]...), format, nil
+		}
+		positional++
+	}
+
+	return SplitOutputFlag(args, def)
+}
+
+func isOutputFlag(name string) bool {
+	return name == "-o" || name == "--output" || name == "-output"
+}
+
+// NormalizeCase lowercases args the way Tokenize lowercases unquoted text,
+// for words that arrive already split, such as program arguments, where
+// there is no telling what was quoted. Words holding whitespace must have
+// been quoted, so they keep their case, as do KeepCase arguments.
+func (c CliCommand) NormalizeCase(args []string) []string {
+	words := make([]string, len(args))
+	positional := 0
+	for i := 0; i < len(args); i++ {
+		words[i] = args[i]
+		if strings.ContainsFunc(args[i], unicode.IsSpace) {
+			positional++
+			continue
+		}
+
+		name, _, hasValue := strings.Cut(strings.ToLower(args[i]), "=")
+		if isOutputFlag(name) {
+			words[i] = strings.ToLower(args[i])
+			if !hasValue && i+1 < len(args) {
+				i++
+				words[i] = strings.ToLower(args[i])
+			}
+			continue
+		}
+
+		if arg, ok := c.argAt(positional); !ok || !arg.KeepCase {
+			words[i] = strings.ToLower(args[i])
+		}
+		positional++
+	}
+	return words
+}
+
+// argAt returns the declared argument that the positional word at index i
+// fills, which for a trailing Variadic argument includes every word past
+// the others.
+func (c CliCommand) argAt(i int) (Arg, bool) {
+	if i < len(c.Args) {
+		return c.Args[i], true
+	}
+	if len(c.Args) > 0 && c.Args[len(c.Args)-1].Variadic {
+		return c.Args[len(c.Args)-1], true
+	}
+	return Arg{}, false
+}
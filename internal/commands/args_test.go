@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/roninii/pokedexcli/internal/output"
+)
+
+func TestValidateArgs(t *testing.T) {
+	cases := []struct {
+		args     []Arg
+		input    []string
+		usage    string
+		expected bool
+	}{
+		{
+			args:     []Arg{{Name: "pokemon"}},
+			input:    []string{},
+			usage:    "test <pokemon>",
+			expected: false,
+		},
+		{
+			args:     []Arg{{Name: "pokemon"}},
+			input:    []string{"pikachu"},
+			usage:    "test <pokemon>",
+			expected: true,
+		},
+		{
+			args:     []Arg{{Name: "pokemon"}},
+			input:    []string{"pikachu", "pidgey"},
+			usage:    "test <pokemon>",
+			expected: false,
+		},
+		{
+			args:     []Arg{{Name: "pokemon"}, {Name: "nickname", Optional: true}},
+			input:    []string{"", "sparky"},
+			usage:    "test <pokemon> [nickname]",
+			expected: false,
+		},
+		{
+			args:     []Arg{{Name: "pokemon"}, {Name: "nickname", Optional: true}},
+			input:    []string{"pikachu", ""},
+			usage:    "test <pokemon> [nickname]",
+			expected: true,
+		},
+		{
+			args:     []Arg{{Name: "max-areas", Optional: true}},
+			input:    []string{},
+			usage:    "test [max-areas]",
+			expected: true,
+		},
+		{
+			args:     []Arg{{Name: "command"}, {Name: "args", Optional: true, Variadic: true}},
+			input:    []string{"catch", "pikachu", "pidgey"},
+			usage:    "test <command> [args]...",
+			expected: true,
+		},
+		{
+			args:     nil,
+			input:    []string{"extra"},
+			usage:    "test",
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		command := CliCommand{Name: "test", Args: c.args}
+
+		if usage := command.Usage(); usage != c.usage {
+			t.Errorf("Expected usage %q, got %q", c.usage, usage)
+		}
+
+		err := command.ValidateArgs(c.input)
+		if c.expected && err != nil {
+			t.Errorf("Expected %v to be valid for %s, got %v", c.input, c.usage, err)
+		}
+		if !c.expected && (err == nil || err.Error() != "usage: "+c.usage) {
+			t.Errorf("Expected usage error for %v with %s, got %v", c.input, c.usage, err)
+		}
+	}
+}
+
+func TestSplitOutputFlag(t *testing.T) {
+	cases := []struct {
+		input    []string
+		rest     []string
+		expected output.Format
+		err      bool
+	}{
+		{input: []string{"pikachu"}, rest: []string{"pikachu"}, expected: output.Text},
+		{input: []string{"pikachu", "-o", "json"}, rest: []string{"pikachu"}, expected: output.JSON},
+		{input: []string{"--output=json", "pikachu"}, rest: []string{"pikachu"}, expected: output.JSON},
+		{input: []string{"pikachu", "-o"}, err: true},
+		{input: []string{"-o", "yaml"}, err: true},
+	}
+
+	for _, c := range cases {
+		rest, format, err := SplitOutputFlag(c.input, output.Text)
+		if c.err {
+			if err == nil {
+				t.Errorf("Expected an error for %v", c.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", c.input, err)
+			continue
+		}
+
+		if format != c.expected {
+			t.Errorf("Expected format %s for %v, got %s", c.expected, c.input, format)
+		}
+		if strings.Join(rest, " ") != strings.Join(c.rest, " ") {
+			t.Errorf("Expected remaining args %v for %v, got %v", c.rest, c.input, rest)
+		}
+	}
+}
+
+func TestSplitOutputFlagVerbatim(t *testing.T) {
+	command := Commands["alias"]
+	cases := []struct {
+		input    []string
+		rest     []string
+		expected output.Format
+	}{
+		{input: []string{"-o", "json"}, expected: output.JSON},
+		{input: []string{"mj", "-o", "json"}, rest: []string{"mj"}, expected: output.JSON},
+		{input: []string{"mj", "map", "-o", "json"}, rest: []string{"mj", "map", "-o", "json"}, expected: output.Text},
+		{input: []string{"-o", "json", "mj", "map", "--output=text"}, rest: []string{"mj", "map", "--output=text"}, expected: output.JSON},
+		{input: []string{"--output=json", "mj"}, rest: []string{"mj"}, expected: output.JSON},
+	}
+
+	for _, c := range cases {
+		rest, format, err := command.SplitOutputFlag(c.input, output.Text)
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", c.input, err)
+			continue
+		}
+		if format != c.expected {
+			t.Errorf("Expected format %s for %v, got %s", c.expected, c.input, format)
+		}
+		if strings.Join(rest, " ") != strings.Join(c.rest, " ") {
+			t.Errorf("Expected remaining args %v for %v, got %v", c.rest, c.input, rest)
+		}
+	}
+}
+
+func TestNormalizeCase(t *testing.T) {
+	cases := []struct {
+		command  string
+		input    []string
+		expected []string
+	}{
+		{command: "explore", input: []string{"Eterna-Forest-Area"}, expected: []string{"eterna-forest-area"}},
+		{command: "catch", input: []string{"Pidgey", "Sparky"}, expected: []string{"pidgey", "Sparky"}},
+		{command: "catch", input: []string{"--OUTPUT=JSON", "Pidgey", "Sky King"}, expected: []string{"--output=json", "pidgey", "Sky King"}},
+		{command: "catch", input: []string{"-O", "JSON", "Pidgey", "Sparky"}, expected: []string{"-o", "json", "pidgey", "Sparky"}},
+		{command: "alias", input: []string{"EF", "Explore", "Eterna-Forest-Area"}, expected: []string{"ef", "explore", "eterna-forest-area"}},
+		{command: "macro", input: []string{"Hunt", "catch $1 Sparky"}, expected: []string{"hunt", "catch $1 Sparky"}},
+	}
+
+	for _, c := range cases {
+		actual := Commands[c.command].NormalizeCase(c.input)
+		if strings.Join(actual, "|") != strings.Join(c.expected, "|") {
+			t.Errorf("Expected %q for %s %q, got %q", c.expected, c.command, c.input, actual)
+		}
+	}
+}
@@ -1,141 +1,367 @@
 package commands
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"math/rand"
-	"net/http"
-	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 	"time"
 
+	"github.com/roninii/pokedexcli/internal/alias"
+	"github.com/roninii/pokedexcli/internal/history"
+	"github.com/roninii/pokedexcli/internal/output"
 	"github.com/roninii/pokedexcli/internal/pokeapi"
-	"github.com/roninii/pokedexcli/internal/pokecache"
 	"github.com/roninii/pokedexcli/internal/pokedex"
+	"github.com/roninii/pokedexcli/internal/settings"
 )
 
 type CliCommand struct {
 	Name        string
 	Description string
-	Callback    func(*Config, []string) error
+	// Category groups the command in help output; see Categories.
+	Category string
+	Args     []Arg
+	// Examples are complete command lines shown by "help <command>".
+	Examples []string
+	// Aliases are alternative names the command can be run by.
+	Aliases  []string
+	Callback func(context.Context, *Config, []string) error
 }
 
+// Categories lists the command categories in the order help shows them.
+var Categories = []string{"navigation", "catching", "collection", "system"}
+
 type Config struct {
+	Client *pokeapi.Client
+	// Settings holds the defaults main resolved from the config file,
+	// environment and flags.
+	Settings settings.Settings
+	// Out is the printer for the command currently running, which may
+	// override Settings.Output.
+	Out    *output.Printer
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// History is nil when commands are not being recorded.
+	History *history.History
+	// Aliases holds user-defined aliases and macros; nil disables them.
+	Aliases *alias.Store
+	// Rand drives catch attempts; nil uses the global source.
+	Rand *rand.Rand
+	// Now stamps caught Pokemon; nil uses time.Now.
+	Now      func() time.Time
 	Next     string
 	Previous string
+
+	// area is the location area last explored, and levels the range of
+	// levels each of its Pokemon is met at, used to record where and at
+	// what level a Pokemon was caught.
+	area   string
+	levels map[string]levelRange
+
+	// seenAreas and seenPokemon feed tab completion.
+	seenAreas   map[string]bool
+	seenPokemon map[string]bool
 }
 
 var Commands map[string]CliCommand
-var cache pokecache.Cache
+
+// ErrExit is returned by the exit command to ask the caller to end the
+// session once the Pokedex has been saved.
+var ErrExit = errors.New("exit requested")
 
 func init() {
-	cache = pokecache.NewCache(5 * time.Second)
 	Commands = map[string]CliCommand{
 		"exit": {
 			Name:        "exit",
 			Description: "Close the Pokedex",
+			Category:    "system",
+			Aliases:     []string{"quit", "q"},
 			Callback:    CommandExit,
 		},
 		"help": {
 			Name:        "help",
-			Description: "Show available commands",
-			Callback:    CommandHelp,
+			Description: "Show available commands, or details about one command.",
+			Category:    "system",
+			Args: []Arg{{
+				Name:        "command",
+				Description: "command to describe",
+				Optional:    true,
+				Complete:    func(*Config) []string { return commandNames() },
+			}},
+			Examples: []string{"help", "help catch"},
+			Aliases:  []string{"h"},
+			Callback: CommandHelp,
 		},
 		"map": {
 			Name:        "map",
 			Description: "Show a paginated list of map locations; subsequent calls will show the next page of results.",
+			Category:    "navigation",
 			Callback:    CommandMap,
 		},
 		"mapb": {
 			Name:        "mapb",
 			Description: "Show the previous page of map locations.",
+			Category:    "navigation",
 			Callback:    CommandMapb,
 		},
 		"explore": {
 			Name:        "explore",
 			Description: "Show a list of Pokemon in a given location.",
-			Callback:    CommandExplore,
+			Category:    "navigation",
+			Args: []Arg{{
+				Name:        "location-area",
+				Description: "location area name, as listed by map",
+				Complete:    seenAreas,
+			}},
+			Examples: []string{"explore eterna-forest-area"},
+			Aliases:  []string{"ex"},
+			Callback: CommandExplore,
 		},
 		"catch": {
 			Name:        "catch",
 			Description: "Attempt to catch the specified Pokemon.",
-			Callback:    CommandCatch,
+			Category:    "catching",
+			Args: []Arg{
+				{Name: "pokemon", Description: "Pokemon name, as listed by explore", Complete: seenPokemon},
+				{Name: "nickname", Description: "name to give the Pokemon if it is caught", Optional: true, KeepCase: true},
+			},
+			Examples: []string{"catch pikachu", `catch pikachu "Sparky"`},
+			Aliases:  []string{"c"},
+			Callback: CommandCatch,
 		},
 		"inspect": {
 			Name:        "inspect",
-			Description: "Inspect a caught Pokemon.",
-			Callback:    CommandInspect,
+			Description: "Inspect a caught species and every specimen of it, or a single specimen by ID.",
+			Category:    "collection",
+			Args: []Arg{{
+				Name:        "pokemon",
+				Description: "caught species, or specimen ID such as 3 or #3",
+				Complete:    caughtPokemon,
+			}},
+			Examples: []string{"inspect pikachu", "inspect 3"},
+			Aliases:  []string{"i"},
+			Callback: CommandInspect,
 		},
 		"pokedex": {
 			Name:        "pokedex",
-			Description: "List all caught Pokemon.",
-			Callback:    CommandPokedex,
+			Description: "List every caught species as a table, optionally sorted and filtered.",
+			Category:    "collection",
+			Args: []Arg{{
+				Name:        "options",
+				Description: "--sort name|dex|caught|stats, --type <type>, --min-weight <weight>",
+				Optional:    true,
+				Variadic:    true,
+			}},
+			Examples: []string{"pokedex", "pokedex --sort stats", "pokedex --type fire --min-weight 100"},
+			Aliases:  []string{"dex"},
+			Callback: CommandPokedex,
+		},
+		"progress": {
+			Name:        "progress",
+			Description: "Show how many species have been seen and caught in each region and in the national Pokedex.",
+			Category:    "collection",
+			Callback:    CommandProgress,
+		},
+		"history": {
+			Name:        "history",
+			Description: "List previously entered commands; re-run one with !n, or the last with !!.",
+			Category:    "system",
+			Args: []Arg{{
+				Name:        "count",
+				Description: "number of recent commands to list",
+				Optional:    true,
+			}},
+			Examples: []string{"history", "history 10", "!!", "!3"},
+			Callback: CommandHistory,
+		},
+		"snapshot": {
+			Name:        "snapshot",
+			Description: "Download the location areas and Pokemon used by the Pokedex for offline use, optionally limited to a number of areas.",
+			Category:    "system",
+			Args: []Arg{{
+				Name:        "max-areas",
+				Description: "stop after this many location areas",
+				Optional:    true,
+			}},
+			Examples: []string{"snapshot", "snapshot 5"},
+			Callback: CommandSnapshot,
+		},
+		"alias": {
+			Name:        "alias",
+			Description: "List aliases, show one, or define a shorthand for a command and its arguments.",
+			Category:    "system",
+			Args: []Arg{
+				{Name: "name", Description: "alias to show or define", Optional: true, Complete: userAliases},
+				{Name: "command", Description: "command, with any arguments, that the alias runs", Optional: true, Variadic: true, Verbatim: true},
+			},
+			Examples: []string{"alias", "alias cp catch pikachu", "alias ef explore eterna-forest-area", "alias mj map -o json"},
+			Callback: CommandAlias,
+		},
+		"macro": {
+			Name:        "macro",
+			Description: "List macros, show one, or define a named sequence of commands; $1, $2... are replaced by the macro's arguments.",
+			Category:    "system",
+			Args: []Arg{
+				{Name: "name", Description: "macro to show or define", Optional: true, Complete: userAliases},
+				{Name: "command", Description: "quoted command line to run, in order", Optional: true, Variadic: true, Verbatim: true},
+			},
+			Examples: []string{`macro hunt "explore $1" "catch $2"`, "hunt eterna-forest-area pikachu"},
+			Callback: CommandMacro,
+		},
+		"unalias": {
+			Name:        "unalias",
+			Description: "Remove a user-defined alias or macro.",
+			Category:    "system",
+			Args:        []Arg{{Name: "name", Description: "alias or macro to remove", Complete: userAliases}},
+			Callback:    CommandUnalias,
 		},
 	}
 }
 
-func CleanInput(input string) []string {
-	if input == "" {
-		return []string{}
+func CommandExit(ctx context.Context, config *Config, args []string) error {
+	if err := pokedex.Save(config.Settings.SavePath); err != nil {
+		fmt.Fprintf(config.Stderr, "Error saving the Pokedex: %v\n", err)
 	}
-	return strings.Split(strings.ToLower(strings.TrimSpace(input)), " ")
+
+	config.Out.Println("Closing the Pokedex... Goodbye!")
+
+	return ErrExit
 }
 
-func CommandExit(config *Config, args []string) error {
-	fmt.Println("Closing the Pokedex... Goodbye!")
-	os.Exit(0)
+type commandView struct {
+	Name        string   `json:"name"`
+	Category    string   `json:"category"`
+	Description string   `json:"description"`
+	Usage       string   `json:"usage"`
+	Aliases     []string `json:"aliases,omitempty"`
+}
 
-	return nil
+type argView struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Optional    bool   `json:"optional"`
+	Variadic    bool   `json:"variadic"`
 }
 
-func CommandHelp(config *Config, args []string) error {
-	fmt.Println("Welcome to the Pokedex!")
-	fmt.Println("Available commands:")
-	fmt.Println("")
+type commandDetailView struct {
+	commandView
+	Args     []argView `json:"args"`
+	Examples []string  `json:"examples"`
+}
 
+func newCommandView(command CliCommand) commandView {
+	return commandView{
+		Name:        command.Name,
+		Category:    command.Category,
+		Description: command.Description,
+		Usage:       command.Usage(),
+		Aliases:     command.Aliases,
+	}
+}
+
+// CommandHelp lists the commands grouped by category, or describes the
+// command named by its argument in detail.
+func CommandHelp(ctx context.Context, config *Config, args []string) error {
+	if len(args) > 0 {
+		command, ok := Lookup(strings.ToLower(args[0]))
+		if !ok {
+			return fmt.Errorf("Unknown command: %s", args[0])
+		}
+		return helpCommand(config.Out, command)
+	}
+
+	out := config.Out
+	out.Println("Welcome to the Pokedex!")
+	out.Println("Available commands:")
+
+	byCategory := map[string][]CliCommand{}
 	for _, command := range Commands {
-		fmt.Printf("%s: %s\n", command.Name, command.Description)
+		byCategory[command.Category] = append(byCategory[command.Category], command)
 	}
 
+	for _, category := range Categories {
+		commands := byCategory[category]
+		sort.Slice(commands, func(i, j int) bool {
+			return commands[i].Name < commands[j].Name
+		})
+
+		out.Println("")
+		out.Printf("%s:\n", strings.ToUpper(category[:1])+category[1:])
+		for _, command := range commands {
+			err := out.Emit(newCommandView(command), func(w io.Writer) {
+				name := command.Name
+				if len(command.Aliases) > 0 {
+					name += " (" + strings.Join(command.Aliases, ", ") + ")"
+				}
+				fmt.Fprintf(w, "  %s: %s\n", name, command.Description)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	out.Println("")
+	out.Println(`Use "help <command>" for more about a command.`)
+
 	return nil
 }
 
-func CommandMap(config *Config, args []string) error {
-	var url string
-	if config.Next != "" {
-		url = config.Next
-	} else {
-		url = pokeapi.LocationAreaURL
+func helpCommand(out *output.Printer, command CliCommand) error {
+	view := commandDetailView{
+		commandView: newCommandView(command),
+		Args:        []argView{},
+		Examples:    command.Examples,
+	}
+	for _, arg := range command.Args {
+		view.Args = append(view.Args, argView{
+			Name:        arg.Name,
+			Description: arg.Description,
+			Optional:    arg.Optional,
+			Variadic:    arg.Variadic,
+		})
+	}
+	if view.Examples == nil {
+		view.Examples = []string{}
 	}
 
-	var mapData pokeapi.Response
+	return out.Emit(view, func(w io.Writer) {
+		fmt.Fprintf(w, "%s: %s\n", command.Name, command.Description)
+		fmt.Fprintf(w, "\nUsage:\n  %s\n", command.Usage())
 
-	if val, exists := cache.Get(url); exists {
-		err := json.Unmarshal(val, &mapData)
-		if err != nil {
-			return fmt.Errorf("Error decoding map data: %v", err)
-		}
-	} else {
-		res, err := http.Get(url)
-		if err != nil {
-			return fmt.Errorf("Error fetching map data: %v", err)
+		if len(command.Args) > 0 {
+			fmt.Fprintln(w, "\nArguments:")
+			tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			for _, arg := range command.Args {
+				fmt.Fprintf(tw, "  %s\t%s\n", arg, arg.Description)
+			}
+			tw.Flush()
 		}
 
-		defer res.Body.Close()
-
-		decoder := json.NewDecoder(res.Body)
-		err = decoder.Decode(&mapData)
-		if err != nil {
-			return fmt.Errorf("Error decoding map data: %v", err)
+		if len(command.Examples) > 0 {
+			fmt.Fprintln(w, "\nExamples:")
+			for _, example := range command.Examples {
+				fmt.Fprintf(w, "  %s\n", example)
+			}
 		}
 
-		responseBytes, err := json.Marshal(mapData)
-		if err != nil {
-			fmt.Printf("Error adding response to cache: %v\n", err)
-		} else {
-			cache.Add(url, responseBytes)
+		if len(command.Aliases) > 0 {
+			fmt.Fprintf(w, "\nAliases: %s\n", strings.Join(command.Aliases, ", "))
 		}
+	})
+}
+
+func CommandMap(ctx context.Context, config *Config, args []string) error {
+	mapData, err := config.Client.ListLocationAreas(ctx, config.Next)
+	if err != nil {
+		return apiError(err, "map data", "Error fetching map data")
 	}
 
 	config.Next = mapData.Next
@@ -143,43 +369,17 @@ func CommandMap(config *Config, args []string) error {
 		config.Previous = *mapData.Previous
 	}
 
-	printEntries(mapData.Results)
-
-	return nil
+	return printEntries(config, mapData.Results)
 }
 
-func CommandMapb(config *Config, args []string) error {
+func CommandMapb(ctx context.Context, config *Config, args []string) error {
 	if config.Previous == "" {
 		return fmt.Errorf("Already at the beginning of the map!")
 	}
 
-	var mapData pokeapi.Response
-
-	if val, exists := cache.Get(config.Previous); exists {
-		err := json.Unmarshal(val, &mapData)
-		if err != nil {
-			return fmt.Errorf("Error decoding map data: %v", err)
-		}
-	} else {
-		res, err := http.Get(config.Previous)
-		if err != nil {
-			return fmt.Errorf("Error fetching map data: %v", err)
-		}
-
-		defer res.Body.Close()
-		decoder := json.NewDecoder(res.Body)
-		err = decoder.Decode(&mapData)
-		if err != nil {
-			return fmt.Errorf("Error decoding map data: %v", err)
-		}
-
-		responseBytes, err := json.Marshal(mapData)
-		if err != nil {
-			fmt.Printf("Error adding response to cache: %v\n", err)
-		} else {
-			cache.Add(config.Previous, responseBytes)
-		}
-
+	mapData, err := config.Client.ListLocationAreas(ctx, config.Previous)
+	if err != nil {
+		return apiError(err, "map data", "Error fetching map data")
 	}
 
 	config.Next = mapData.Next
@@ -190,143 +390,335 @@ func CommandMapb(config *Config, args []string) error {
 		config.Previous = ""
 	}
 
-	printEntries(mapData.Results)
-
-	return nil
+	return printEntries(config, mapData.Results)
 }
 
-func CommandExplore(config *Config, args []string) error {
+func CommandExplore(ctx context.Context, config *Config, args []string) error {
 	location := args[0]
-	url := fmt.Sprintf("%s%s", pokeapi.LocationAreaURL, location)
 
-	var areaData pokeapi.ExploreResponse
+	areaData, err := config.Client.GetLocationArea(ctx, location)
+	if err != nil {
+		return apiError(err, fmt.Sprintf("location area named '%s'", location), "Error fetching Pokemon data at location "+location)
+	}
 
-	if val, exists := cache.Get(url); exists {
-		err := json.Unmarshal(val, &areaData)
+	config.area = location
+	config.levels = map[string]levelRange{}
+	config.Out.Println("")
+	for _, encounter := range areaData.PokemonEncounters {
+		config.rememberPokemon(encounter.Pokemon.Name)
+		pokedex.MarkSeen(encounter.Pokemon.Name, encounter.Pokemon.ID())
+		low, high := encounter.LevelRange()
+		config.levels[encounter.Pokemon.Name] = levelRange{low: low, high: high}
+		err := config.Out.Emit(encounter.Pokemon, func(w io.Writer) {
+			fmt.Fprintln(w, encounter.Pokemon.Name)
+		})
 		if err != nil {
-			return fmt.Errorf("Error decoding Pokemon data at location %s: %v", location, err)
+			return err
 		}
-	} else {
-		res, err := http.Get(url)
-		if err != nil {
-			return fmt.Errorf("Error fetching Pokemon data at location %s: %v", location, err)
+	}
+
+	if err := pokedex.Save(config.Settings.SavePath); err != nil {
+		return fmt.Errorf("Error saving the Pokedex: %v", err)
+	}
+
+	return nil
+}
+
+type catchView struct {
+	Name   string `json:"name"`
+	Caught bool   `json:"caught"`
+	// ID and Level describe the new specimen when Caught is true.
+	ID    int `json:"id,omitempty"`
+	Level int `json:"level,omitempty"`
+}
+
+// levelRange is the span of levels a Pokemon is met at in an area.
+type levelRange struct {
+	low, high int
+}
+
+// defaultLevel is given to Pokemon caught without being met in the last
+// explored area, or whose area does not say what level they are.
+const defaultLevel = 5
+
+func CommandCatch(ctx context.Context, config *Config, args []string) error {
+	out := config.Out
+	pokemon := args[0]
+	out.Printf("Throwing a Pokeball at %s...\n", pokemon)
+
+	pokemonData, err := config.Client.GetPokemon(ctx, pokemon)
+	if err != nil {
+		return apiError(err, fmt.Sprintf("Pokemon named '%s'", pokemon), "Error fetching Pokemon data for "+pokemon)
+	}
+
+	baseCatchRate := math.Max(10, float64(100-pokemonData.BaseExperience))
+	roll := config.random() * 100
+	caught := roll <= baseCatchRate
+
+	pokedex.MarkSeen(pokemonData.Name, pokemonData.ID)
+	view := catchView{Name: pokemon, Caught: caught}
+	if caught {
+		caughtAt := config.now()
+		specimen := pokedex.Specimen{CaughtAt: &caughtAt, Level: defaultLevel}
+		if args := args[1:]; len(args) > 0 {
+			specimen.Nickname = args[0]
+		}
+		if levels, ok := config.levels[pokemonData.Name]; ok {
+			specimen.Location = config.area
+			if levels.high > 0 {
+				specimen.Level = levels.low + int(config.random()*float64(levels.high-levels.low+1))
+			}
 		}
 
-		defer res.Body.Close()
-		decoder := json.NewDecoder(res.Body)
-		err = decoder.Decode(&areaData)
-		if err != nil {
-			return fmt.Errorf("Error decoding Pokemon data at location %s: %v", location, err)
+		specimen = pokedex.Catch(pokemonData, specimen)
+		view.ID, view.Level = specimen.ID, specimen.Level
+	}
+	if err := pokedex.Save(config.Settings.SavePath); err != nil {
+		return fmt.Errorf("Error saving the Pokedex: %v", err)
+	}
+
+	return out.Emit(view, func(w io.Writer) {
+		if !caught {
+			fmt.Fprintf(w, "%s escaped!\n", pokemon)
+			return
 		}
+		fmt.Fprintf(w, "%s was caught!\n", pokemon)
+		fmt.Fprintf(w, "Adding %s to the Pokedex as #%d (level %d)...\n", pokemon, view.ID, view.Level)
+		fmt.Fprintf(w, "Done! You may now view details about %s with the inspect command.\n", pokemon)
+	})
+}
 
-		responseBytes, err := json.Marshal(areaData)
-		if err != nil {
-			fmt.Printf("Error adding response to cache: %v\n", err)
-		} else {
-			cache.Add(config.Previous, responseBytes)
+type statView struct {
+	Name     string `json:"name"`
+	BaseStat int    `json:"base_stat"`
+}
+
+type inspectView struct {
+	Name      string             `json:"name"`
+	Height    int                `json:"height"`
+	Weight    int                `json:"weight"`
+	Stats     []statView         `json:"stats"`
+	Types     []string           `json:"types"`
+	Specimens []pokedex.Specimen `json:"specimens"`
+}
+
+// CommandInspect shows a caught species along with every specimen of it, or
+// just one specimen when given its ID.
+func CommandInspect(ctx context.Context, config *Config, args []string) error {
+	name := args[0]
+
+	var specimens []pokedex.Specimen
+	if id, err := strconv.Atoi(strings.TrimPrefix(name, "#")); err == nil {
+		specimen, ok := pokedex.FindSpecimen(id)
+		if !ok {
+			return fmt.Errorf("No caught Pokemon has ID %d.", id)
 		}
+		name = specimen.Species
+		specimens = []pokedex.Specimen{specimen}
+	} else {
+		specimens = pokedex.SpecimensOf(name)
 	}
 
-	fmt.Println("")
-	for _, encounter := range areaData.PokemonEncounters {
-		fmt.Println(encounter.Pokemon.Name)
+	pokemon, ok := pokedex.Pokedex[name]
+	if !ok {
+		return fmt.Errorf("%s has not been caught.\n", name)
 	}
 
-	return nil
+	view := inspectView{
+		Name:      pokemon.Name,
+		Height:    pokemon.Height,
+		Weight:    pokemon.Weight,
+		Stats:     []statView{},
+		Types:     []string{},
+		Specimens: specimens,
+	}
+	for _, stat := range pokemon.Stats {
+		view.Stats = append(view.Stats, statView{Name: stat.Stat.Name, BaseStat: stat.BaseStat})
+	}
+	for _, t := range pokemon.Types {
+		view.Types = append(view.Types, t.Type.Name)
+	}
+
+	return config.Out.Emit(view, func(w io.Writer) {
+		fmt.Fprintf(w, "Name: %s\n", view.Name)
+		fmt.Fprintf(w, "Height: %d\n", view.Height)
+		fmt.Fprintf(w, "Weight: %d\n", view.Weight)
+		fmt.Fprintln(w, "Stats:")
+		for _, stat := range view.Stats {
+			fmt.Fprintf(w, "  - %s: %d\n", stat.Name, stat.BaseStat)
+		}
+		fmt.Fprintln(w, "Types:")
+		for _, t := range view.Types {
+			fmt.Fprintf(w, "  - %s\n", t)
+		}
+		fmt.Fprintln(w, "Specimens:")
+		for _, specimen := range view.Specimens {
+			fmt.Fprintf(w, "  - %s\n", describeSpecimen(specimen))
+		}
+	})
 }
 
-func CommandCatch(config *Config, args []string) error {
-	pokemon := args[0]
-	url := fmt.Sprintf("%s%s", pokeapi.PokemonURL, pokemon)
-	fmt.Printf("Throwing a Pokeball at %s...\n", pokemon)
+// describeSpecimen summarises a specimen in one line, for example
+// "#3 Sparky (pikachu), level 10, caught 2024-05-01 12:00 in eterna-forest-area".
+func describeSpecimen(s pokedex.Specimen) string {
+	description := fmt.Sprintf("#%d %s", s.ID, s.Name())
+	if s.Nickname != "" {
+		description += " (" + s.Species + ")"
+	}
+	if s.Level > 0 {
+		description += fmt.Sprintf(", level %d", s.Level)
+	}
+	if s.CaughtAt != nil {
+		description += ", caught " + s.CaughtAt.Format("2006-01-02 15:04")
+	}
+	if s.Location != "" {
+		description += " in " + s.Location
+	}
+	return description
+}
 
-	var pokemonData pokeapi.Pokemon
-	if val, exists := cache.Get(url); exists {
-		err := json.Unmarshal(val, &pokemonData)
-		if err != nil {
-			return fmt.Errorf("Error decoding data for %s: %v", pokemon, err)
+type progressView struct {
+	Region     string `json:"region"`
+	Generation int    `json:"generation,omitempty"`
+	Seen       int    `json:"seen"`
+	Caught     int    `json:"caught"`
+	Total      int    `json:"total"`
+}
+
+// CommandProgress reports how many species have been seen and caught in
+// each generation and across the national dex.
+func CommandProgress(ctx context.Context, config *Config, args []string) error {
+	national, byGeneration := pokedex.Completion()
+
+	table := config.Out.Table("Region", "Gen", "Seen", "Caught", "Total")
+	for i, g := range pokedex.Generations {
+		progress := byGeneration[i]
+		view := progressView{
+			Region:     g.Region,
+			Generation: g.Number,
+			Seen:       progress.Seen,
+			Caught:     progress.Caught,
+			Total:      progress.Total,
 		}
-	} else {
-		res, err := http.Get(url)
-		if err != nil {
-			return fmt.Errorf("Error fetching Pokemon data for %s: %v", pokemon, err)
+		if err := table.Row(view, view.Region, view.Generation, view.Seen, view.Caught, view.Total); err != nil {
+			return err
 		}
+	}
 
-		defer res.Body.Close()
-		decoder := json.NewDecoder(res.Body)
-		err = decoder.Decode(&pokemonData)
-		if err != nil {
-			return fmt.Errorf("Error decoding Pokemon data for %s: %v", pokemon, err)
-		}
+	view := progressView{Region: "National", Seen: national.Seen, Caught: national.Caught, Total: national.Total}
+	if err := table.Row(view, view.Region, "", view.Seen, view.Caught, view.Total); err != nil {
+		return err
+	}
+	return table.Flush()
+}
 
-		responseBytes, err := json.Marshal(pokemonData)
-		if err != nil {
-			fmt.Printf("Error adding response to cache: %v\n", err)
-		} else {
-			cache.Add(config.Previous, responseBytes)
-		}
+type historyView struct {
+	Number  int    `json:"number"`
+	Command string `json:"command"`
+}
+
+func CommandHistory(ctx context.Context, config *Config, args []string) error {
+	if config.History == nil {
+		return fmt.Errorf("History is not available")
 	}
 
-	baseCatchRate := math.Max(10, float64(100-pokemonData.BaseExperience))
-	roll := rand.Float64() * 100
+	entries := config.History.Entries()
+	start := 0
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("Invalid number of commands: %s", args[0])
+		}
+		start = max(len(entries)-n, 0)
+	}
 
-	if roll <= baseCatchRate {
-		fmt.Printf("%s was caught!\n", pokemon)
-		fmt.Printf("Adding %s to the Pokedex...\n", pokemon)
-		fmt.Printf("Done! You may now view details about %s with the inspect command.\n", pokemon)
-		pokedex.AddPokemon(pokemonData)
-	} else {
-		fmt.Printf("%s escaped!\n", pokemon)
+	for i := start; i < len(entries); i++ {
+		view := historyView{Number: i + 1, Command: entries[i]}
+		err := config.Out.Emit(view, func(w io.Writer) {
+			fmt.Fprintf(w, "%5d  %s\n", view.Number, view.Command)
+		})
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func CommandInspect(config *Config, args []string) error {
-	pokedex := pokedex.Pokedex
-	name := args[0]
-	if pokemon, ok := pokedex[name]; ok {
-		// TODO: loop through values in the name, height, wieght, stats, and types and print them out
-		for _, key := range []string{"Name", "Height", "Weight", "Stats", "Types"} {
-			switch key {
-			case "Name":
-				fmt.Printf("Name: %s\n", pokemon.Name)
-			case "Height":
-				fmt.Printf("Height: %d\n", pokemon.Height)
-			case "Weight":
-				fmt.Printf("Weight: %d\n", pokemon.Weight)
-			case "Stats":
-				fmt.Println("Stats:")
-				for _, stat := range pokemon.Stats {
-					fmt.Printf("  - %s: %d\n", stat.Stat.Name, stat.BaseStat)
-				}
-			case "Types":
-				fmt.Println("Types:")
-				for _, t := range pokemon.Types {
-					fmt.Printf("  - %s\n", t.Type.Name)
-				}
+type snapshotView struct {
+	Path string `json:"path"`
+}
 
-			}
+func CommandSnapshot(ctx context.Context, config *Config, args []string) error {
+	if config.Settings.Offline {
+		return fmt.Errorf("Cannot take a snapshot while offline")
+	}
+
+	maxAreas := 0
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("Invalid number of areas: %s", args[0])
 		}
+		maxAreas = n
+	}
 
-		return nil
+	out := config.Out
+	out.Printf("Saving snapshot to %s...\n", config.Settings.SnapshotDir)
+	err := config.Client.Snapshot(ctx, config.Settings.SnapshotDir, maxAreas, func(path string) {
+		out.Emit(snapshotView{Path: path}, func(w io.Writer) {
+			fmt.Fprintf(w, "  - %s\n", path)
+		})
+	})
+	if err != nil {
+		return apiError(err, "data for the snapshot", "Error taking snapshot")
 	}
 
-	return fmt.Errorf("%s has not been caught.\n", name)
+	out.Println("Done! Start the Pokedex with --offline to use it.")
+	return nil
 }
 
-func CommandPokedex(config *Config, args []string) error {
-	for name := range pokedex.Pokedex {
-		fmt.Printf("  - %s\n", name)
-		return nil
+func (c *Config) now() time.Time {
+	if c.Now == nil {
+		return time.Now()
 	}
+	return c.Now()
+}
 
-	return fmt.Errorf("No Pokemon have been caught yet.")
+func (c *Config) random() float64 {
+	if c.Rand == nil {
+		return rand.Float64()
+	}
+	return c.Rand.Float64()
 }
 
-func printEntries(entries []pokeapi.Results) {
-	fmt.Println("")
+// apiError turns the typed errors returned by pokeapi into messages meant
+// for the player. Anything unrecognised is reported as-is after prefix.
+func apiError(err error, subject string, prefix string) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return context.Canceled
+	case errors.Is(err, pokeapi.ErrNotFound):
+		return fmt.Errorf("no %s", subject)
+	case errors.Is(err, pokeapi.ErrRateLimited):
+		return fmt.Errorf("PokeAPI is rate limiting requests, please wait a moment and try again")
+	case errors.Is(err, pokeapi.ErrServer):
+		return fmt.Errorf("PokeAPI is having problems right now, please try again later")
+	}
+	return fmt.Errorf("%s: %v", prefix, err)
+}
+
+func printEntries(config *Config, entries []pokeapi.Results) error {
+	out := config.Out
+	out.Println("")
 	for _, location := range entries {
-		fmt.Println(location.Name)
+		config.rememberAreas(location.Name)
+		err := out.Emit(location, func(w io.Writer) {
+			fmt.Fprintln(w, location.Name)
+		})
+		if err != nil {
+			return err
+		}
 	}
+	return nil
 }
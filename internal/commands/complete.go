@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/roninii/pokedexcli/internal/pokedex"
+)
+
+// Complete implements lineedit.Completer for the REPL. The first word
+// completes to a command name; later words use the Complete function of the
+// matching Arg, if it has one.
+func (c *Config) Complete(line string) (int, []string) {
+	start := strings.LastIndexAny(line, " \t") + 1
+	words := strings.Fields(line[:start])
+	prefix := strings.ToLower(line[start:])
+
+	var options []string
+	if len(words) == 0 {
+		options = append(commandNames(), userAliases(c)...)
+		for _, command := range Commands {
+			options = append(options, command.Aliases...)
+		}
+	} else {
+		command, ok := Lookup(strings.ToLower(words[0]))
+		if !ok || len(command.Args) == 0 {
+			return start, nil
+		}
+
+		i := len(words) - 1
+		if i >= len(command.Args) {
+			if !command.Args[len(command.Args)-1].Variadic {
+				return start, nil
+			}
+			i = len(command.Args) - 1
+		}
+		if command.Args[i].Complete == nil {
+			return start, nil
+		}
+		options = command.Args[i].Complete(c)
+	}
+
+	candidates := []string{}
+	for _, option := range options {
+		if strings.HasPrefix(option, prefix) {
+			candidates = append(candidates, option)
+		}
+	}
+	sort.Strings(candidates)
+
+	return start, candidates
+}
+
+func (c *Config) rememberAreas(names ...string) {
+	if c.seenAreas == nil {
+		c.seenAreas = map[string]bool{}
+	}
+	for _, name := range names {
+		c.seenAreas[name] = true
+	}
+}
+
+func (c *Config) rememberPokemon(names ...string) {
+	if c.seenPokemon == nil {
+		c.seenPokemon = map[string]bool{}
+	}
+	for _, name := range names {
+		c.seenPokemon[name] = true
+	}
+}
+
+func commandNames() []string {
+	names := make([]string, 0, len(Commands))
+	for name := range Commands {
+		names = append(names, name)
+	}
+	return names
+}
+
+// seenAreas completes location areas listed by map or mapb this session.
+func seenAreas(c *Config) []string {
+	return keys(c.seenAreas)
+}
+
+// seenPokemon completes Pokemon listed by explore this session.
+func seenPokemon(c *Config) []string {
+	return keys(c.seenPokemon)
+}
+
+func caughtPokemon(c *Config) []string {
+	names := make([]string, 0, len(pokedex.Pokedex))
+	for name := range pokedex.Pokedex {
+		names = append(names, name)
+	}
+	return names
+}
+
+func keys(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	return names
+}
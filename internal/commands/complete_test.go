@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/roninii/pokedexcli/internal/alias"
+	"github.com/roninii/pokedexcli/internal/pokedex"
+)
+
+func TestComplete(t *testing.T) {
+	config, _ := newTestConfig(t)
+	run(t, config, "map", "explore eterna-forest-area")
+	pokedex.Catch(pokedex.Pokemon{Name: "rattata"}, pokedex.Specimen{})
+	config.Aliases, _ = alias.Load("")
+	config.Aliases.SetAlias("ef", []string{"explore", "eterna-forest-area"})
+
+	cases := []struct {
+		line     string
+		start    int
+		expected []string
+	}{
+		{line: "", start: 0, expected: []string{"alias", "c", "catch", "dex", "ef", "ex", "exit", "explore", "h", "help", "history", "i", "inspect", "macro", "map", "mapb", "pokedex", "progress", "q", "quit", "snapshot", "unalias"}},
+		{line: "ex", start: 0, expected: []string{"ex", "exit", "explore"}},
+		{line: "c pi", start: 2, expected: []string{"pidgey", "pikachu"}},
+		{line: "unalias ", start: 8, expected: []string{"ef"}},
+		{line: "explore et", start: 8, expected: []string{"eterna-city-area", "eterna-forest-area"}},
+		{line: "EXPLORE  Eterna-f", start: 9, expected: []string{"eterna-forest-area"}},
+		{line: "catch pi", start: 6, expected: []string{"pidgey", "pikachu"}},
+		{line: "inspect ", start: 8, expected: []string{"rattata"}},
+		{line: "catch pidgey ", start: 13, expected: nil},
+		{line: "map ", start: 4, expected: nil},
+		{line: "bogus ", start: 6, expected: nil},
+	}
+
+	for _, c := range cases {
+		start, candidates := config.Complete(c.line)
+		if start != c.start {
+			t.Errorf("Expected completion of %q to start at %d, got %d", c.line, c.start, start)
+		}
+		if strings.Join(candidates, ",") != strings.Join(c.expected, ",") {
+			t.Errorf("Expected completions %v for %q, got %v", c.expected, c.line, candidates)
+		}
+	}
+}
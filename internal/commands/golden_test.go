@@ -0,0 +1,337 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/roninii/pokedexcli/internal/alias"
+	"github.com/roninii/pokedexcli/internal/history"
+	"github.com/roninii/pokedexcli/internal/output"
+	"github.com/roninii/pokedexcli/internal/pokeapitest"
+	"github.com/roninii/pokedexcli/internal/pokedex"
+	"github.com/roninii/pokedexcli/internal/settings"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with the current output")
+
+// newTestConfig returns a Config backed by a fake PokeAPI server, with a
+// seeded random source and a temporary save file.
+func newTestConfig(t *testing.T) (*Config, *pokeapitest.Server) {
+	t.Helper()
+	pokedex.Reset()
+
+	server := pokeapitest.NewServer()
+	t.Cleanup(server.Close)
+	dir := t.TempDir()
+
+	return &Config{
+		Client: server.NewClient(),
+		Settings: settings.Settings{
+			Output:      output.Text,
+			SavePath:    filepath.Join(dir, "pokedex.json"),
+			SnapshotDir: filepath.Join(dir, "snapshot"),
+		},
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+		Rand:   rand.New(rand.NewSource(6)),
+		Now: func() time.Time {
+			return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		},
+	}, server
+}
+
+// run executes each line of input as a command, the way the REPL would, and
+// returns everything they printed along with any errors.
+func run(t *testing.T, config *Config, input ...string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	config.Stdout = &buf
+	config.Stderr = &buf
+
+	for _, line := range input {
+		fmt.Fprintf(&buf, "> %s\n", line)
+
+		words, err := Tokenize(line)
+		if err != nil || len(words) == 0 {
+			t.Fatalf("Invalid input %q: %v", line, err)
+		}
+		commands, err := config.Expand(words)
+		if err != nil {
+			fmt.Fprintf(&buf, "error: %v\n", err)
+			continue
+		}
+
+		for _, words := range commands {
+			command, ok := Lookup(words[0])
+			if !ok {
+				fmt.Fprintf(&buf, "error: unknown command %s\n", words[0])
+				break
+			}
+
+			args, format, err := command.SplitOutputFlag(words[1:], config.Settings.Output)
+			if err == nil {
+				err = command.ValidateArgs(args)
+			}
+			if err == nil {
+				config.Out = output.New(&buf, format)
+				err = command.Callback(context.Background(), config, args)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrExit) {
+					fmt.Fprintf(&buf, "error: %v\n", err)
+				}
+				break
+			}
+		}
+	}
+
+	return buf.String()
+}
+
+func assertGolden(t *testing.T, name string, actual string) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name+".golden")
+
+	if *update {
+		if err := os.WriteFile(path, []byte(actual), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Reading golden file (run with -update to create it): %v", err)
+	}
+	if actual != string(expected) {
+		t.Errorf("Output does not match %s\n--- expected\n%s\n--- actual\n%s", path, expected, actual)
+	}
+}
+
+func TestCommandMetadata(t *testing.T) {
+	names := map[string]string{}
+	for name := range Commands {
+		names[name] = name
+	}
+	for name, command := range Commands {
+		if !slices.Contains(Categories, command.Category) {
+			t.Errorf("%s has unknown category %q", name, command.Category)
+		}
+		for _, alias := range command.Aliases {
+			if other, ok := names[alias]; ok {
+				t.Errorf("Alias %s of %s is also a name for %s", alias, name, other)
+			}
+			names[alias] = name
+		}
+	}
+}
+
+func TestCommandsGolden(t *testing.T) {
+	cases := []struct {
+		name  string
+		setup func(config *Config)
+		input []string
+	}{
+		{
+			name:  "help",
+			input: []string{"help"},
+		},
+		{
+			name:  "help_json",
+			input: []string{"help -o json"},
+		},
+		{
+			name:  "help_command",
+			input: []string{"help catch", "help map", "help c", "help nope"},
+		},
+		{
+			name:  "help_command_json",
+			input: []string{"help history -o json"},
+		},
+		{
+			name:  "map",
+			input: []string{"map", "map", "mapb", "mapb"},
+		},
+		{
+			name:  "map_json",
+			input: []string{"map -o json"},
+		},
+		{
+			name:  "explore",
+			input: []string{"explore eterna-forest-area", "explore eterna-forest-area -o json", "explore nowhere"},
+		},
+		{
+			name:  "catch",
+			input: []string{"catch pidgey", "catch pidgey", "catch pikachu -o json", "catch missingno"},
+		},
+		{
+			name: "inspect",
+			setup: func(config *Config) {
+				p, _ := config.Client.GetPokemon(context.Background(), "pidgey")
+				pokedex.Catch(p, pokedex.Specimen{})
+			},
+			input: []string{"inspect pidgey", "inspect pidgey -o json", "inspect pikachu"},
+		},
+		{
+			name: "specimens",
+			input: []string{
+				"explore eterna-forest-area",
+				`catch pidgey "Birdy"`,
+				"catch pidgey",
+				"catch pidgey",
+				"catch pidgey",
+				"inspect pidgey",
+				"inspect 1",
+				"inspect #2 -o json",
+				"inspect 99",
+			},
+		},
+		{
+			name:  "progress",
+			input: []string{"progress", "explore canalave-city-area", "catch pidgey", "progress", "progress -o json"},
+		},
+		{
+			name:  "pokedex_empty",
+			input: []string{"pokedex"},
+		},
+		{
+			name: "pokedex",
+			setup: func(config *Config) {
+				caughtAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+				for i, name := range []string{"rattata", "pikachu", "pidgey", "rattata"} {
+					p, _ := config.Client.GetPokemon(context.Background(), name)
+					at := caughtAt.Add(time.Duration(-i) * time.Hour)
+					pokedex.Catch(p, pokedex.Specimen{CaughtAt: &at})
+				}
+			},
+			input: []string{
+				"pokedex",
+				"pokedex --sort dex",
+				"pokedex --sort caught",
+				"pokedex --sort stats",
+				"pokedex --type flying",
+				"pokedex --min-weight 40",
+				"pokedex --type fire",
+				"pokedex --sort colour",
+				"pokedex --shiny",
+				"pokedex sideways",
+			},
+		},
+		{
+			name: "pokedex_json",
+			setup: func(config *Config) {
+				pokedex.Catch(pokedex.Pokemon{Name: "pidgey"}, pokedex.Specimen{})
+			},
+			input: []string{"pokedex -o json"},
+		},
+		{
+			name:  "history_unavailable",
+			input: []string{"history"},
+		},
+		{
+			name: "history",
+			setup: func(config *Config) {
+				config.History, _ = history.Load("")
+				for _, line := range []string{"map", "explore eterna-forest-area", "catch pidgey"} {
+					config.History.Add(line)
+				}
+			},
+			input: []string{"history", "history 2 -o json"},
+		},
+		{
+			name:  "aliases_unavailable",
+			input: []string{"alias", "macro"},
+		},
+		{
+			name: "aliases",
+			setup: func(config *Config) {
+				config.Aliases, _ = alias.Load("")
+			},
+			input: []string{
+				"alias ef explore eterna-forest-area",
+				"alias cf canalave-city-area",
+				"alias catch map",
+				"ef",
+				"alias mj map -o json",
+				"mj",
+				"alias",
+				"alias -o json ef",
+				"alias ef -o json",
+				"unalias ef",
+				"ef",
+			},
+		},
+		{
+			name: "macros",
+			setup: func(config *Config) {
+				config.Aliases, _ = alias.Load("")
+			},
+			input: []string{
+				`macro hunt "explore $1" "catch $2"`,
+				"hunt canalave-city-area rattata",
+				"hunt canalave-city-area",
+				`macro loop "loop"`,
+				"loop",
+				"macro",
+				"macro hunt -o json",
+				"ex canalave-city-area",
+			},
+		},
+		{
+			name:  "snapshot",
+			input: []string{"snapshot 1"},
+		},
+		{
+			name: "snapshot_offline",
+			setup: func(config *Config) {
+				config.Settings.Offline = true
+			},
+			input: []string{"snapshot"},
+		},
+		{
+			name:  "exit",
+			input: []string{"exit"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config, server := newTestConfig(t)
+			if c.setup != nil {
+				c.setup(config)
+			}
+
+			actual := run(t, config, c.input...)
+			actual = strings.ReplaceAll(actual, config.Settings.SnapshotDir, "<snapshot-dir>")
+			actual = strings.ReplaceAll(actual, server.URL, "<server>")
+			assertGolden(t, c.name, actual)
+		})
+	}
+}
+
+func TestExitSavesPokedex(t *testing.T) {
+	config, _ := newTestConfig(t)
+	pokedex.Catch(pokedex.Pokemon{Name: "pidgey"}, pokedex.Specimen{})
+
+	run(t, config, "exit")
+
+	pokedex.Reset()
+	if err := pokedex.Load(config.Settings.SavePath); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := pokedex.Pokedex["pidgey"]; !ok {
+		t.Errorf("Expected exit to save the Pokedex")
+	}
+}
@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMapNavigation(t *testing.T) {
+	config, server := newTestConfig(t)
+
+	steps := []struct {
+		input    string
+		first    string
+		next     string
+		previous string
+		err      bool
+	}{
+		{input: "mapb", err: true},
+		{input: "map", first: "canalave-city-area", next: "offset=20"},
+		{input: "map", first: "mt-coronet-1f-route-216", previous: "offset=0"},
+		{input: "mapb", first: "canalave-city-area", next: "offset=20"},
+		{input: "mapb", err: true},
+	}
+
+	for _, step := range steps {
+		out := run(t, config, step.input)
+		if strings.Contains(out, "error:") != step.err {
+			t.Fatalf("%s: unexpected output %q", step.input, out)
+		}
+		if step.err {
+			continue
+		}
+
+		if lines := strings.Split(out, "\n"); len(lines) < 3 || lines[2] != step.first {
+			t.Errorf("%s: expected page starting with %s, got %q", step.input, step.first, out)
+		}
+		for _, link := range []struct{ got, want string }{{config.Next, step.next}, {config.Previous, step.previous}} {
+			got, want := link.got, link.want
+			if want == "" && got != "" {
+				t.Errorf("%s: expected no link, got %s", step.input, got)
+			}
+			if want != "" && (!strings.HasPrefix(got, server.BaseURL()) || !strings.Contains(got, want)) {
+				t.Errorf("%s: expected link on the fake server containing %s, got %s", step.input, want, got)
+			}
+		}
+	}
+
+	if requests := len(server.Requests()); requests != 3 {
+		t.Errorf("Expected 3 requests to the server, got %d", requests)
+	}
+}
@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/roninii/pokedexcli/internal/pokedex"
+)
+
+// pokedexSorts are the orders the pokedex command can list species in. Ties
+// are broken by name so the order is always stable.
+var pokedexSorts = map[string]func(a, b pokedexView) bool{
+	// name needs no comparison, since species are listed in name order to
+	// begin with.
+	"name": func(a, b pokedexView) bool {
+		return false
+	},
+	"dex": func(a, b pokedexView) bool {
+		return a.Dex < b.Dex
+	},
+	// caught lists the species caught first at the top, and those from old
+	// saves that never recorded a catch time at the bottom.
+	"caught": func(a, b pokedexView) bool {
+		if a.FirstCaught == nil || b.FirstCaught == nil {
+			return a.FirstCaught != nil && b.FirstCaught == nil
+		}
+		return a.FirstCaught.Before(*b.FirstCaught)
+	},
+	// stats lists the strongest species at the top.
+	"stats": func(a, b pokedexView) bool {
+		return a.BaseStatTotal > b.BaseStatTotal
+	},
+}
+
+type pokedexView struct {
+	Dex           int      `json:"dex"`
+	Name          string   `json:"name"`
+	Types         []string `json:"types"`
+	Weight        int      `json:"weight"`
+	BaseStatTotal int      `json:"base_stat_total"`
+	Caught        int      `json:"caught"`
+	// FirstCaught is the earliest known catch time, and nil if no specimen
+	// of the species recorded one.
+	FirstCaught *time.Time `json:"first_caught,omitempty"`
+}
+
+func newPokedexView(p pokedex.Pokemon) pokedexView {
+	view := pokedexView{
+		Dex:    p.ID,
+		Name:   p.Name,
+		Types:  []string{},
+		Weight: p.Weight,
+	}
+	for _, t := range p.Types {
+		view.Types = append(view.Types, t.Type.Name)
+	}
+	for _, stat := range p.Stats {
+		view.BaseStatTotal += stat.BaseStat
+	}
+	for _, specimen := range pokedex.SpecimensOf(p.Name) {
+		view.Caught++
+		if specimen.CaughtAt == nil {
+			continue
+		}
+		if view.FirstCaught == nil || specimen.CaughtAt.Before(*view.FirstCaught) {
+			view.FirstCaught = specimen.CaughtAt
+		}
+	}
+	return view
+}
+
+// CommandPokedex lists the caught species. Its arguments are flags:
+// --sort picks the order, and --type and --min-weight filter the list.
+func CommandPokedex(ctx context.Context, config *Config, args []string) error {
+	flags := flag.NewFlagSet("pokedex", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	sortBy := flags.String("sort", "name", "")
+	typeName := flags.String("type", "", "")
+	minWeight := flags.Int("min-weight", 0, "")
+	usage := &UsageError{Usage: "pokedex [--sort name|dex|caught|stats] [--type <type>] [--min-weight <weight>]"}
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%v; %w", err, usage)
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q; %w", flags.Arg(0), usage)
+	}
+	less, ok := pokedexSorts[*sortBy]
+	if !ok {
+		return fmt.Errorf("Unknown sort order %q; expected name, dex, caught or stats", *sortBy)
+	}
+
+	if len(pokedex.Pokedex) == 0 {
+		return fmt.Errorf("No Pokemon have been caught yet.")
+	}
+
+	var views []pokedexView
+	for _, name := range pokedex.CaughtSpecies() {
+		view := newPokedexView(pokedex.Pokedex[name])
+		if *typeName != "" && !slices.Contains(view.Types, *typeName) {
+			continue
+		}
+		if view.Weight < *minWeight {
+			continue
+		}
+		views = append(views, view)
+	}
+	if len(views) == 0 {
+		config.Out.Println("No caught Pokemon match.")
+		return nil
+	}
+	// CaughtSpecies is sorted by name, so a stable sort keeps name order
+	// among ties.
+	sort.SliceStable(views, func(i, j int) bool {
+		return less(views[i], views[j])
+	})
+
+	table := config.Out.Table("Dex", "Name", "Types", "Weight", "Stats", "Caught", "First caught")
+	for _, view := range views {
+		firstCaught := "unknown"
+		if view.FirstCaught != nil {
+			firstCaught = view.FirstCaught.Format("2006-01-02 15:04")
+		}
+		err := table.Row(view, view.Dex, view.Name, strings.Join(view.Types, "/"),
+			view.Weight, view.BaseStatTotal, view.Caught, firstCaught)
+		if err != nil {
+			return err
+		}
+	}
+	return table.Flush()
+}
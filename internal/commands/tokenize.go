@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrUnterminatedQuote  = errors.New("unterminated quote")
+	ErrUnterminatedEscape = errors.New("trailing backslash")
+)
+
+// Tokenize splits a line of REPL input into words. Words are separated by
+// runs of whitespace; single quotes preserve everything up to the closing
+// quote, double quotes do the same but allow \" and \\ escapes, and a
+// backslash outside quotes escapes the next character. Unquoted text is
+// lowercased so that names typed in any case match PokeAPI's slugs, while
+// quoted and escaped text (such as a nickname) keeps its case. The command
+// name, the first word, is always lowercased.
+func Tokenize(input string) ([]string, error) {
+	words := []string{}
+	var word strings.Builder
+	inWord := false
+
+	runes := []rune(input)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+
+		case r == '\\':
+			if i+1 >= len(runes) {
+				return nil, ErrUnterminatedEscape
+			}
+			i++
+			word.WriteRune(runes[i])
+			inWord = true
+
+		case r == '\'' || r == '"':
+			end := i + 1
+			for ; end < len(runes) && runes[end] != r; end++ {
+				if r == '"' && runes[end] == '\\' && end+1 < len(runes) && (runes[end+1] == '"' || runes[end+1] == '\\') {
+					end++
+				}
+				word.WriteRune(runes[end])
+			}
+			if end >= len(runes) {
+				return nil, ErrUnterminatedQuote
+			}
+			i = end
+			inWord = true
+
+		default:
+			word.WriteRune(unicode.ToLower(r))
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	if len(words) > 0 {
+		words[0] = strings.ToLower(words[0])
+	}
+	return words, nil
+}
@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected []string
+	}{
+		{
+			input:    " hello world ",
+			expected: []string{"hello", "world"},
+		},
+		{
+			input:    "HeLlO WoRlD",
+			expected: []string{"hello", "world"},
+		},
+		{
+			input:    "world",
+			expected: []string{"world"},
+		},
+		{
+			input:    "",
+			expected: []string{},
+		},
+		{
+			input:    " \t  ",
+			expected: []string{},
+		},
+		{
+			input:    "catch  pikachu",
+			expected: []string{"catch", "pikachu"},
+		},
+		{
+			input:    `catch pidgey "Sky King"`,
+			expected: []string{"catch", "pidgey", "Sky King"},
+		},
+		{
+			input:    `CATCH pidgey 'Say "Hi"'`,
+			expected: []string{"catch", "pidgey", `Say "Hi"`},
+		},
+		{
+			input:    `catch pidgey "a \"quoted\" \\ name"`,
+			expected: []string{"catch", "pidgey", `a "quoted" \ name`},
+		},
+		{
+			input:    `catch pidgey Sky\ King`,
+			expected: []string{"catch", "pidgey", "sky king"},
+		},
+		{
+			input:    `catch pidgey ""`,
+			expected: []string{"catch", "pidgey", ""},
+		},
+		{
+			input:    `"EXPLORE" mt-'Coronet'`,
+			expected: []string{"explore", "mt-Coronet"},
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := Tokenize(c.input)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %v", c.input, err)
+			continue
+		}
+
+		if len(actual) != len(c.expected) {
+			t.Errorf("Expected length of %d but got %d", len(c.expected), len(actual))
+			continue
+		}
+		for i := range actual {
+			actualWord := actual[i]
+			expectedWord := c.expected[i]
+
+			if actualWord != expectedWord {
+				t.Errorf("Expected %s but got %s", expectedWord, actualWord)
+			}
+		}
+	}
+}
+
+func TestTokenizeErrors(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected error
+	}{
+		{input: `catch "pikachu`, expected: ErrUnterminatedQuote},
+		{input: `catch 'pikachu`, expected: ErrUnterminatedQuote},
+		{input: `catch "pikachu\"`, expected: ErrUnterminatedQuote},
+		{input: `catch pikachu\`, expected: ErrUnterminatedEscape},
+	}
+
+	for _, c := range cases {
+		if _, err := Tokenize(c.input); !errors.Is(err, c.expected) {
+			t.Errorf("Expected %v for %q, got %v", c.expected, c.input, err)
+		}
+	}
+}
+
+// quote renders word so that Tokenize reads it back unchanged.
+func quote(word string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(word) + `"`
+}
+
+func FuzzTokenize(f *testing.F) {
+	for _, seed := range []string{"", "catch pikachu", `catch pidgey "Sky King"`, `a 'b c' d\ e "f\"g"`, "\t\n "} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		words, err := Tokenize(input)
+		if err != nil {
+			return
+		}
+
+		quoted := make([]string, len(words))
+		for i, word := range words {
+			quoted[i] = quote(word)
+		}
+
+		again, err := Tokenize(strings.Join(quoted, " "))
+		if err != nil {
+			t.Fatalf("Re-tokenizing %q failed: %v", quoted, err)
+		}
+		if strings.Join(again, "\x00") != strings.Join(words, "\x00") || len(again) != len(words) {
+			t.Errorf("Round trip of %q changed %q to %q", input, words, again)
+		}
+	})
+}
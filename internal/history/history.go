@@ -0,0 +1,141 @@
+// Package history records the commands entered at the REPL in a file so
+// they can be listed and re-run across sessions.
+package history
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/roninii/pokedexcli/internal/paths"
+)
+
+// MaxEntries is how many commands are kept. Older entries are dropped from
+// the file the next time it is loaded.
+const MaxEntries = 1000
+
+var ErrNoHistory = errors.New("no commands in history")
+
+type History struct {
+	path    string
+	entries []string
+}
+
+// Load reads the history file at path. A missing file gives an empty
+// history. An empty path keeps history in memory only.
+func Load(path string) (*History, error) {
+	h := &History{path: path}
+	if path == "" {
+		return h, nil
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(h.entries) > MaxEntries {
+		h.entries = h.entries[len(h.entries)-MaxEntries:]
+		if err := h.rewrite(); err != nil {
+			return nil, err
+		}
+	}
+
+	return h, nil
+}
+
+// DefaultPath returns the history file location under the XDG data
+// directory.
+func DefaultPath() (string, error) {
+	dataDir, err := paths.DataDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dataDir, "history"), nil
+}
+
+// Entries returns every recorded command, oldest first. Entry n in the
+// numbering shown to players is Entries()[n-1].
+func (h *History) Entries() []string {
+	return h.entries
+}
+
+// Add records line and appends it to the history file.
+func (h *History) Add(line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil
+	}
+
+	h.entries = append(h.entries, line)
+	if h.path == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintln(file, line)
+	return err
+}
+
+// Expand replaces a leading "!!" with the previous command, or "!n" with
+// command number n, keeping anything after it. It reports whether line
+// referred to the history at all.
+func (h *History) Expand(line string) (string, bool, error) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "!") {
+		return line, false, nil
+	}
+
+	ref, rest, _ := strings.Cut(trimmed[1:], " ")
+	var entry string
+	switch {
+	case ref == "!":
+		if len(h.entries) == 0 {
+			return "", true, ErrNoHistory
+		}
+		entry = h.entries[len(h.entries)-1]
+	default:
+		n, err := strconv.Atoi(ref)
+		if err != nil || n < 1 || n > len(h.entries) {
+			return "", true, fmt.Errorf("!%s: event not found", ref)
+		}
+		entry = h.entries[n-1]
+	}
+
+	if rest = strings.TrimSpace(rest); rest != "" {
+		entry += " " + rest
+	}
+	return entry, true, nil
+}
+
+func (h *History) rewrite() error {
+	data := strings.Join(h.entries, "\n") + "\n"
+	return os.WriteFile(h.path, []byte(data), 0o600)
+}
@@ -0,0 +1,90 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	h, _ := Load("")
+	for _, line := range []string{"map", "explore eterna-forest-area", "catch pidgey"} {
+		h.Add(line)
+	}
+
+	cases := []struct {
+		input    string
+		expected string
+		expanded bool
+		err      bool
+	}{
+		{input: "help", expected: "help"},
+		{input: "!!", expected: "catch pidgey", expanded: true},
+		{input: "!2", expected: "explore eterna-forest-area", expanded: true},
+		{input: "!2 -o json", expected: "explore eterna-forest-area -o json", expanded: true},
+		{input: "!4", expanded: true, err: true},
+		{input: "!0", expanded: true, err: true},
+		{input: "!catch", expanded: true, err: true},
+	}
+
+	for _, c := range cases {
+		actual, expanded, err := h.Expand(c.input)
+		if (err != nil) != c.err {
+			t.Errorf("Unexpected error state for %q: %v", c.input, err)
+			continue
+		}
+		if expanded != c.expanded {
+			t.Errorf("Expected expanded to be %v for %q", c.expanded, c.input)
+		}
+		if !c.err && actual != c.expected {
+			t.Errorf("Expected %q to expand to %q, got %q", c.input, c.expected, actual)
+		}
+	}
+}
+
+func TestExpandEmpty(t *testing.T) {
+	h, _ := Load("")
+	if _, _, err := h.Expand("!!"); !errors.Is(err, ErrNoHistory) {
+		t.Errorf("Expected ErrNoHistory, got %v", err)
+	}
+}
+
+func TestPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+
+	h, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Add("map")
+	h.Add("   ")
+	h.Add("catch pidgey")
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries := strings.Join(reloaded.Entries(), ","); entries != "map,catch pidgey" {
+		t.Errorf("Expected history to survive reloading, got %q", entries)
+	}
+}
+
+func TestTrim(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	lines := make([]string, MaxEntries+5)
+	for i := range lines {
+		lines[i] = "map"
+	}
+	lines[5] = "catch pidgey"
+	os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600)
+
+	h, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Entries()) != MaxEntries || h.Entries()[0] != "catch pidgey" {
+		t.Errorf("Expected history trimmed to the newest %d entries", MaxEntries)
+	}
+}
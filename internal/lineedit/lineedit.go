@@ -0,0 +1,239 @@
+// Package lineedit reads lines from a terminal with basic editing, history
+// navigation and tab completion, using only the standard library.
+package lineedit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrInterrupted is returned by ReadLine when the user presses Ctrl-C.
+var ErrInterrupted = errors.New("interrupted")
+
+// Completer returns the candidates for completing the word that ends at the
+// end of line, along with the offset in line where that word starts.
+// Candidates replace line[start:] when chosen.
+type Completer func(line string) (start int, candidates []string)
+
+type Editor struct {
+	in     io.Reader
+	reader *bufio.Reader
+	out    io.Writer
+
+	Prompt   string
+	Complete Completer
+	// History returns previous lines, oldest first, for the up and down
+	// arrow keys.
+	History func() []string
+}
+
+func New(in io.Reader, out io.Writer) *Editor {
+	return &Editor{in: in, reader: bufio.NewReader(in), out: out}
+}
+
+// Supported reports whether f is a terminal that can be put into raw mode.
+func Supported(f *os.File) bool {
+	return isTerminal(f.Fd())
+}
+
+type lineState struct {
+	buf []rune
+	pos int
+}
+
+// ReadLine prints the prompt and reads a line. It returns io.EOF when the
+// user presses Ctrl-D on an empty line and ErrInterrupted on Ctrl-C. The
+// terminal is only in raw mode while a line is being read, so command
+// output and signals behave normally in between.
+func (e *Editor) ReadLine() (string, error) {
+	if f, ok := e.in.(*os.File); ok {
+		state, err := makeRaw(f.Fd())
+		if err != nil {
+			return "", err
+		}
+		defer restore(f.Fd(), state)
+	}
+
+	var history []string
+	if e.History != nil {
+		history = e.History()
+	}
+	historyIndex := len(history)
+	// draft keeps what was typed before browsing history.
+	draft := ""
+
+	s := &lineState{}
+	e.refresh(s)
+
+	for {
+		r, _, err := e.reader.ReadRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			fmt.Fprint(e.out, "\r\n")
+			return string(s.buf), nil
+
+		case 3: // Ctrl-C
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", ErrInterrupted
+
+		case 4: // Ctrl-D
+			if len(s.buf) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			s.delete()
+
+		case 127, 8: // Backspace
+			if s.pos > 0 {
+				s.pos--
+				s.delete()
+			}
+
+		case 1: // Ctrl-A
+			s.pos = 0
+
+		case 5: // Ctrl-E
+			s.pos = len(s.buf)
+
+		case 21: // Ctrl-U
+			s.buf = append([]rune{}, s.buf[s.pos:]...)
+			s.pos = 0
+
+		case '\t':
+			e.complete(s)
+
+		case 27: // Escape sequence
+			switch e.readEscape() {
+			case "[A":
+				if historyIndex > 0 {
+					if historyIndex == len(history) {
+						draft = string(s.buf)
+					}
+					historyIndex--
+					s.set(history[historyIndex])
+				}
+			case "[B":
+				if historyIndex < len(history) {
+					historyIndex++
+					if historyIndex == len(history) {
+						s.set(draft)
+					} else {
+						s.set(history[historyIndex])
+					}
+				}
+			case "[C":
+				s.pos = min(s.pos+1, len(s.buf))
+			case "[D":
+				s.pos = max(s.pos-1, 0)
+			case "[H", "OH", "[1~":
+				s.pos = 0
+			case "[F", "OF", "[4~":
+				s.pos = len(s.buf)
+			case "[3~":
+				s.delete()
+			}
+
+		default:
+			if r >= ' ' {
+				s.insert(string(r))
+			}
+		}
+
+		e.refresh(s)
+	}
+}
+
+// readEscape reads the rest of an ANSI escape sequence after ESC, such as
+// "[A" for the up arrow.
+func (e *Editor) readEscape() string {
+	var seq strings.Builder
+	for {
+		r, _, err := e.reader.ReadRune()
+		if err != nil {
+			return seq.String()
+		}
+		seq.WriteRune(r)
+		// The first character after ESC is '[' or 'O'; the sequence ends
+		// with a letter or '~'.
+		if seq.Len() > 1 && (r == '~' || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z')) {
+			return seq.String()
+		}
+	}
+}
+
+func (e *Editor) complete(s *lineState) {
+	if e.Complete == nil {
+		return
+	}
+
+	before := string(s.buf[:s.pos])
+	start, candidates := e.Complete(before)
+	if len(candidates) == 0 {
+		return
+	}
+	word := before[start:]
+
+	if len(candidates) == 1 {
+		s.replace(start, candidates[0]+" ")
+		return
+	}
+
+	if prefix := commonPrefix(candidates); len(prefix) > len(word) {
+		s.replace(start, prefix)
+		return
+	}
+
+	// Nothing more to fill in, so show the options below the prompt.
+	fmt.Fprint(e.out, "\r\n"+strings.Join(candidates, "  ")+"\r\n")
+}
+
+// refresh redraws the prompt and line, leaving the cursor at s.pos.
+func (e *Editor) refresh(s *lineState) {
+	fmt.Fprintf(e.out, "\r%s%s\x1b[K", e.Prompt, string(s.buf))
+	if back := len(s.buf) - s.pos; back > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dD", back)
+	}
+}
+
+func (s *lineState) insert(text string) {
+	runes := []rune(text)
+	s.buf = append(s.buf[:s.pos], append(runes, s.buf[s.pos:]...)...)
+	s.pos += len(runes)
+}
+
+func (s *lineState) delete() {
+	if s.pos < len(s.buf) {
+		s.buf = append(s.buf[:s.pos], s.buf[s.pos+1:]...)
+	}
+}
+
+func (s *lineState) set(line string) {
+	s.buf = []rune(line)
+	s.pos = len(s.buf)
+}
+
+// replace swaps the text between byte offset start and the cursor for text.
+func (s *lineState) replace(start int, text string) {
+	startRune := len([]rune(string(s.buf[:s.pos])[:start]))
+	s.buf = append(s.buf[:startRune], s.buf[s.pos:]...)
+	s.pos = startRune
+	s.insert(text)
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,74 @@
+package lineedit
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func complete(line string) (int, []string) {
+	words := []string{"catch", "canalave-city-area", "eterna-city-area", "eterna-forest-area"}
+	start := strings.LastIndex(line, " ") + 1
+
+	var candidates []string
+	for _, word := range words {
+		if strings.HasPrefix(word, line[start:]) {
+			candidates = append(candidates, word)
+		}
+	}
+	return start, candidates
+}
+
+func TestReadLine(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+		err      error
+	}{
+		{name: "plain", input: "map\r", expected: "map"},
+		{name: "backspace", input: "mapp\x7f\r", expected: "map"},
+		{name: "cursor movement", input: "ap\x1b[D\x1b[Dm\x1b[F b\r", expected: "map b"},
+		{name: "home and delete", input: "xmap\x01\x1b[3~\r", expected: "map"},
+		{name: "clear line", input: "help\x15map\r", expected: "map"},
+		{name: "single completion", input: "cat\t\r", expected: "catch "},
+		{name: "common prefix", input: "explore et\t\r", expected: "explore eterna-"},
+		{name: "completion mid word", input: "explore eterna-f\t\r", expected: "explore eterna-forest-area "},
+		{name: "history", input: "\x1b[A\x1b[A\r", expected: "map"},
+		{name: "history back to draft", input: "ex\x1b[A\x1b[B\r", expected: "ex"},
+		{name: "unicode", input: "catch flabébé\x7f\x7fé\r", expected: "catch flabéé"},
+		{name: "interrupt", input: "catch\x03", err: ErrInterrupted},
+		{name: "end of input", input: "\x04", err: io.EOF},
+	}
+
+	for _, c := range cases {
+		var out strings.Builder
+		editor := New(strings.NewReader(c.input), &out)
+		editor.Prompt = "Pokedex > "
+		editor.Complete = complete
+		editor.History = func() []string { return []string{"map", "explore canalave-city-area"} }
+
+		line, err := editor.ReadLine()
+		if !errors.Is(err, c.err) {
+			t.Errorf("%s: expected error %v, got %v", c.name, c.err, err)
+			continue
+		}
+		if line != c.expected {
+			t.Errorf("%s: expected %q, got %q", c.name, c.expected, line)
+		}
+	}
+}
+
+func TestCompletionListsCandidates(t *testing.T) {
+	var out strings.Builder
+	editor := New(strings.NewReader("explore eterna-\t\r"), &out)
+	editor.Complete = complete
+
+	if _, err := editor.ReadLine(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "eterna-city-area  eterna-forest-area") {
+		t.Errorf("Expected ambiguous completion to list candidates, got %q", out.String())
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+
+package lineedit
+
+import "errors"
+
+type termState struct{}
+
+func makeRaw(fd uintptr) (*termState, error) {
+	return nil, errors.New("raw terminal mode is not supported on this platform")
+}
+
+func restore(fd uintptr, state *termState) error {
+	return nil
+}
+
+func isTerminal(fd uintptr) bool {
+	return false
+}
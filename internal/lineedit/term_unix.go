@@ -0,0 +1,59 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package lineedit
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+type termState struct {
+	termios syscall.Termios
+}
+
+func getTermios(fd uintptr) (*syscall.Termios, error) {
+	var t syscall.Termios
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlGetTermios, uintptr(unsafe.Pointer(&t))); errno != 0 {
+		return nil, errno
+	}
+	return &t, nil
+}
+
+func setTermios(fd uintptr, t *syscall.Termios) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlSetTermios, uintptr(unsafe.Pointer(t))); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// makeRaw puts the terminal into raw mode so keys arrive one at a time
+// without echo, returning the previous state for restore. Output
+// processing is left alone so "\n" still moves to the start of the line.
+func makeRaw(fd uintptr) (*termState, error) {
+	old, err := getTermios(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := *old
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+
+	if err := setTermios(fd, &raw); err != nil {
+		return nil, err
+	}
+	return &termState{termios: *old}, nil
+}
+
+func restore(fd uintptr, state *termState) error {
+	return setTermios(fd, &state.termios)
+}
+
+func isTerminal(fd uintptr) bool {
+	_, err := getTermios(fd)
+	return err == nil
+}
@@ -0,0 +1,117 @@
+// Package output renders command results either as human-readable text or
+// as JSON for consumption by scripts.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+type Format string
+
+const (
+	Text Format = "text"
+	JSON Format = "json"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case Text, JSON:
+		return Format(s), nil
+	}
+	return "", fmt.Errorf("unknown output format %q (expected %q or %q)", s, Text, JSON)
+}
+
+// Printer writes a command's output in a single format. In JSON mode each
+// call to Emit writes one compact JSON document per line, so a command that
+// emits a list produces newline-delimited JSON and one that emits a single
+// value produces a plain JSON document. Text-only messages are dropped in
+// JSON mode so they never corrupt the stream.
+type Printer struct {
+	w      io.Writer
+	format Format
+}
+
+func New(w io.Writer, format Format) *Printer {
+	return &Printer{w: w, format: format}
+}
+
+func (p *Printer) Format() Format {
+	return p.format
+}
+
+// Emit writes v as JSON, or calls text to render it for humans.
+func (p *Printer) Emit(v any, text func(w io.Writer)) error {
+	if p.format == JSON {
+		return json.NewEncoder(p.w).Encode(v)
+	}
+	text(p.w)
+	return nil
+}
+
+func (p *Printer) Printf(format string, args ...any) {
+	if p.format == Text {
+		fmt.Fprintf(p.w, format, args...)
+	}
+}
+
+func (p *Printer) Println(args ...any) {
+	if p.format == Text {
+		fmt.Fprintln(p.w, args...)
+	}
+}
+
+// Table emits a list of values that reads as an aligned table in text mode.
+// In JSON mode each row is emitted like Emit, and the header is dropped.
+type Table struct {
+	p  *Printer
+	tw *tabwriter.Writer
+}
+
+// Table starts a table, writing header as its first line in text mode.
+// Call Flush once every row has been added.
+func (p *Printer) Table(header ...string) *Table {
+	t := &Table{p: p}
+	if p.format == Text {
+		t.tw = tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+		t.writeCells(stringsToAny(header))
+	}
+	return t
+}
+
+// Row adds v to the table, shown in text mode as one cell per element of
+// cells.
+func (t *Table) Row(v any, cells ...any) error {
+	if t.tw == nil {
+		return t.p.Emit(v, nil)
+	}
+	t.writeCells(cells)
+	return nil
+}
+
+func (t *Table) Flush() error {
+	if t.tw == nil {
+		return nil
+	}
+	return t.tw.Flush()
+}
+
+func (t *Table) writeCells(cells []any) {
+	for i, cell := range cells {
+		if i > 0 {
+			fmt.Fprint(t.tw, "\t")
+		}
+		fmt.Fprint(t.tw, cell)
+	}
+	fmt.Fprintln(t.tw)
+}
+
+func stringsToAny(s []string) []any {
+	cells := make([]any, len(s))
+	for i, v := range s {
+		cells[i] = v
+	}
+	return cells
+}
@@ -0,0 +1,95 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestPrinter(t *testing.T) {
+	type entry struct {
+		Name string `json:"name"`
+	}
+
+	cases := []struct {
+		format   Format
+		expected string
+	}{
+		{
+			format:   Text,
+			expected: "Results:\npidgey\nrattata\n",
+		},
+		{
+			format:   JSON,
+			expected: "{\"name\":\"pidgey\"}\n{\"name\":\"rattata\"}\n",
+		},
+	}
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+		p := New(&buf, c.format)
+
+		p.Println("Results:")
+		for _, name := range []string{"pidgey", "rattata"} {
+			err := p.Emit(entry{Name: name}, func(w io.Writer) {
+				fmt.Fprintln(w, name)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if buf.String() != c.expected {
+			t.Errorf("Expected %q for %s output, got %q", c.expected, c.format, buf.String())
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat("json"); err != nil || f != JSON {
+		t.Errorf("Expected json to parse, got %q (%v)", f, err)
+	}
+	if _, err := ParseFormat("yaml"); err == nil {
+		t.Errorf("Expected an error for an unknown format")
+	}
+}
+
+func TestTable(t *testing.T) {
+	type row struct {
+		Name   string `json:"name"`
+		Caught int    `json:"caught"`
+	}
+	rows := []row{{Name: "pidgey", Caught: 2}, {Name: "pikachu", Caught: 10}}
+
+	cases := []struct {
+		format   Format
+		expected string
+	}{
+		{
+			format:   Text,
+			expected: "Name     Caught\npidgey   2\npikachu  10\n",
+		},
+		{
+			format:   JSON,
+			expected: "{\"name\":\"pidgey\",\"caught\":2}\n{\"name\":\"pikachu\",\"caught\":10}\n",
+		},
+	}
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+		table := New(&buf, c.format).Table("Name", "Caught")
+		for _, r := range rows {
+			if err := table.Row(r, r.Name, r.Caught); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := table.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		if buf.String() != c.expected {
+			t.Errorf("Expected %q for %s output, got %q", c.expected, c.format, buf.String())
+		}
+	}
+}
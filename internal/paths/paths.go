@@ -0,0 +1,46 @@
+// Package paths locates the per-user directories the CLI stores its files
+// in, following the XDG base directory conventions.
+package paths
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const appName = "pokedexcli"
+
+// DataDir returns the directory for persistent user data such as the save
+// file, falling back to ~/.local/share when XDG_DATA_HOME is unset.
+func DataDir() (string, error) {
+	dataDir := os.Getenv("XDG_DATA_HOME")
+	if dataDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dataDir = filepath.Join(home, ".local", "share")
+	}
+
+	return filepath.Join(dataDir, appName), nil
+}
+
+// CacheDir returns the directory for data that can safely be deleted.
+func CacheDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(cacheDir, appName), nil
+}
+
+// ConfigDir returns the directory for user-edited configuration, such as
+// aliases and settings.
+func ConfigDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, appName), nil
+}
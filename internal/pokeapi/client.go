@@ -0,0 +1,154 @@
+package pokeapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Cache is the subset of pokecache.Cache used by the client to avoid
+// refetching responses it has already seen.
+type Cache interface {
+	Add(key string, val []byte)
+	Get(key string) ([]byte, bool)
+}
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	cache      Cache
+	retry      RetryPolicy
+}
+
+// NewClient returns a client for the PokeAPI rooted at baseURL. A nil
+// httpClient falls back to one with a sensible timeout, and a nil cache
+// disables caching.
+func NewClient(baseURL string, httpClient *http.Client, cache Cache) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &Client{
+		baseURL:    baseURL,
+		httpClient: httpClient,
+		cache:      cache,
+		retry:      DefaultRetryPolicy,
+	}
+}
+
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.retry = policy
+}
+
+// ListLocationAreas fetches a page of location areas. An empty pageURL
+// fetches the first page; otherwise it should be a Next or Previous link
+// from an earlier response.
+func (c *Client) ListLocationAreas(ctx context.Context, pageURL string) (Response, error) {
+	if pageURL == "" {
+		pageURL = c.baseURL + locationAreaPath
+	}
+
+	var page Response
+	err := c.get(ctx, pageURL, &page)
+	return page, err
+}
+
+func (c *Client) GetLocationArea(ctx context.Context, name string) (ExploreResponse, error) {
+	var area ExploreResponse
+	err := c.get(ctx, c.baseURL+locationAreaPath+url.PathEscape(name), &area)
+	return area, err
+}
+
+func (c *Client) GetPokemon(ctx context.Context, name string) (Pokemon, error) {
+	var pokemon Pokemon
+	err := c.get(ctx, c.baseURL+pokemonPath+url.PathEscape(name), &pokemon)
+	return pokemon, err
+}
+
+func (c *Client) get(ctx context.Context, url string, v any) error {
+	_, err := c.getRaw(ctx, url, func(body []byte) error {
+		return json.Unmarshal(body, v)
+	})
+	return err
+}
+
+// getRaw returns the body of url from the cache or the network after
+// passing it to decode. Fresh responses are only cached once decode accepts
+// them, so a body that cannot be decoded is never served from the cache.
+func (c *Client) getRaw(ctx context.Context, url string, decode func([]byte) error) ([]byte, error) {
+	if c.cache != nil {
+		if val, exists := c.cache.Get(url); exists {
+			return val, decode(val)
+		}
+	}
+
+	body, err := c.fetch(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := decode(body); err != nil {
+		return nil, fmt.Errorf("decoding response from %s: %w", url, err)
+	}
+
+	if c.cache != nil {
+		c.cache.Add(url, body)
+	}
+
+	return body, nil
+}
+
+// fetch requests url, retrying transient failures according to the
+// client's retry policy. Cancelling ctx aborts both the request in flight
+// and any pending retry.
+func (c *Client) fetch(ctx context.Context, url string) ([]byte, error) {
+	attempts := max(c.retry.MaxAttempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		body, retryAfter, err := c.fetchOnce(ctx, url)
+		if err == nil {
+			return body, nil
+		}
+		if attempt >= attempts || ctx.Err() != nil || !isRetryable(err) {
+			return nil, err
+		}
+
+		timer := time.NewTimer(c.retry.wait(attempt, retryAfter))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) fetchOnce(ctx context.Context, url string) ([]byte, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer res.Body.Close()
+
+	// Error bodies (PokeAPI answers 404s with plain text) are never decoded
+	// or cached.
+	if err := checkStatus(res); err != nil {
+		return nil, parseRetryAfter(res), err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return body, 0, nil
+}
@@ -0,0 +1,175 @@
+package pokeapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type mapCache map[string][]byte
+
+func (m mapCache) Add(key string, val []byte) { m[key] = val }
+
+func (m mapCache) Get(key string) ([]byte, bool) {
+	val, ok := m[key]
+	return val, ok
+}
+
+func TestGetPokemon(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/pokemon/pikachu" {
+			t.Errorf("Unexpected request path %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"id": 25, "name": "pikachu", "base_experience": 112}`)
+	}))
+	defer server.Close()
+
+	cache := mapCache{}
+	client := NewClient(server.URL, server.Client(), cache)
+
+	for i := 0; i < 2; i++ {
+		pokemon, err := client.GetPokemon(context.Background(), "pikachu")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if pokemon.Name != "pikachu" || pokemon.ID != 25 {
+			t.Errorf("Expected pikachu #25, got %s #%d", pokemon.Name, pokemon.ID)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected 1 request with caching, got %d", requests)
+	}
+	if _, exists := cache.Get(server.URL + "/pokemon/pikachu"); !exists {
+		t.Errorf("Expected response to be cached")
+	}
+}
+
+func TestGetEscapesNames(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath()+"?"+r.URL.RawQuery)
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, server.Client(), nil)
+	client.GetPokemon(context.Background(), "../berry/1")
+	client.GetLocationArea(context.Background(), "?offset=20")
+
+	expected := []string{"/pokemon/..%2Fberry%2F1?", "/location-area/%3Foffset=20?"}
+	if fmt.Sprint(paths) != fmt.Sprint(expected) {
+		t.Errorf("Expected requests %v, got %v", expected, paths)
+	}
+}
+
+func TestListLocationAreas(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/location-area/" {
+			t.Errorf("Unexpected request path %s", r.URL.Path)
+		}
+		fmt.Fprintf(w, `{"count": 1, "next": "%s/location-area/?offset=20", "previous": null, "results": [{"name": "canalave-city-area"}]}`, "http://"+r.Host)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, server.Client(), nil)
+	page, err := client.ListLocationAreas(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(page.Results) != 1 || page.Results[0].Name != "canalave-city-area" {
+		t.Errorf("Unexpected results %v", page.Results)
+	}
+	if page.Previous != nil {
+		t.Errorf("Expected no previous page, got %s", *page.Previous)
+	}
+}
+
+func TestStatusErrors(t *testing.T) {
+	cases := []struct {
+		status   int
+		expected error
+	}{
+		{status: http.StatusNotFound, expected: ErrNotFound},
+		{status: http.StatusTooManyRequests, expected: ErrRateLimited},
+		{status: http.StatusInternalServerError, expected: ErrServer},
+		{status: http.StatusServiceUnavailable, expected: ErrServer},
+	}
+
+	for _, c := range cases {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, http.StatusText(c.status), c.status)
+		}))
+
+		cache := mapCache{}
+		client := NewClient(server.URL, server.Client(), cache)
+		client.SetRetryPolicy(RetryPolicy{MaxAttempts: 1})
+		_, err := client.GetPokemon(context.Background(), "pikachoo")
+		server.Close()
+
+		if !errors.Is(err, c.expected) {
+			t.Errorf("Expected %v for status %d, got %v", c.expected, c.status, err)
+		}
+		if len(cache) != 0 {
+			t.Errorf("Expected error response for status %d not to be cached", c.status)
+		}
+	}
+}
+
+func TestCancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(server.URL, server.Client(), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	_, err := client.GetPokemon(ctx, "pikachu")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestLevelRange(t *testing.T) {
+	var encounter PokemonEncounters
+	data := `{"version_details": [
+		{"encounter_details": [{"min_level": 9, "max_level": 10}]},
+		{"encounter_details": [{"min_level": 11, "max_level": 12}, {"min_level": 7, "max_level": 8}]}
+	]}`
+	if err := json.Unmarshal([]byte(data), &encounter); err != nil {
+		t.Fatal(err)
+	}
+
+	if low, high := encounter.LevelRange(); low != 7 || high != 12 {
+		t.Errorf("Expected levels 7-12, got %d-%d", low, high)
+	}
+	if low, high := (PokemonEncounters{}).LevelRange(); low != 0 || high != 0 {
+		t.Errorf("Expected no levels without details, got %d-%d", low, high)
+	}
+}
+
+func TestEncounterID(t *testing.T) {
+	cases := map[string]int{
+		"https://pokeapi.co/api/v2/pokemon/16/": 16,
+		"https://pokeapi.co/api/v2/pokemon/25":  25,
+		"https://pokeapi.co/api/v2/pokemon/":    0,
+		"":                                      0,
+	}
+	for url, expected := range cases {
+		if id := (PokemonEncounter{URL: url}).ID(); id != expected {
+			t.Errorf("Expected ID %d for %q, got %d", expected, url, id)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package pokeapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrRateLimited = errors.New("rate limited")
+	ErrServer      = errors.New("server error")
+)
+
+// StatusError reports a non-2xx response. It wraps one of the sentinel
+// errors above when the status code falls into a known category, so callers
+// can match with errors.Is.
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+func (e *StatusError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode >= 500:
+		return ErrServer
+	}
+	return nil
+}
+
+func checkStatus(res *http.Response) error {
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return nil
+	}
+	return &StatusError{URL: res.Request.URL.String(), StatusCode: res.StatusCode}
+}
@@ -1,9 +1,16 @@
 package pokeapi
 
+import (
+	"path"
+	"strconv"
+	"strings"
+)
+
 const (
-	BaseURL         = "https://pokeapi.co/api/v2"
-	LocationAreaURL = BaseURL + "/location-area/"
-	PokemonURL      = BaseURL + "/pokemon/"
+	BaseURL = "https://pokeapi.co/api/v2"
+
+	locationAreaPath = "/location-area/"
+	pokemonPath      = "/pokemon/"
 )
 
 type Response struct {
@@ -27,8 +34,42 @@ type PokemonEncounter struct {
 	URL  string `json:"url"`
 }
 
+// ID returns the Pokemon's national dex number, taken from the end of its
+// URL, or 0 if the URL does not end in one.
+func (p PokemonEncounter) ID() int {
+	id, err := strconv.Atoi(path.Base(strings.TrimSuffix(p.URL, "/")))
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
 type PokemonEncounters struct {
-	Pokemon PokemonEncounter `json:"pokemon"`
+	Pokemon        PokemonEncounter         `json:"pokemon"`
+	VersionDetails []VersionEncounterDetail `json:"version_details"`
+}
+
+type VersionEncounterDetail struct {
+	EncounterDetails []EncounterDetail `json:"encounter_details"`
+}
+
+type EncounterDetail struct {
+	MinLevel int `json:"min_level"`
+	MaxLevel int `json:"max_level"`
+}
+
+// LevelRange returns the lowest and highest levels the Pokemon is met at in
+// the area across all game versions, or zeros if the area does not say.
+func (e PokemonEncounters) LevelRange() (low, high int) {
+	for _, version := range e.VersionDetails {
+		for _, detail := range version.EncounterDetails {
+			if low == 0 || detail.MinLevel < low {
+				low = detail.MinLevel
+			}
+			high = max(high, detail.MaxLevel)
+		}
+	}
+	return low, high
 }
 
 type PokemonResponse struct {
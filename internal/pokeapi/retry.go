@@ -0,0 +1,103 @@
+package pokeapi
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how transient failures (timeouts, dropped
+// connections, 429s and 5xx responses) are retried. MaxAttempts counts the
+// first request, so a value of 1 disables retrying.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// backoff returns the delay before the given retry (starting at 1). The
+// delay doubles each attempt up to MaxDelay, and a random half of it is
+// jittered so that clients do not retry in lockstep.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < retry && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// wait returns how long to sleep before the given retry, preferring the
+// server's Retry-After hint when there is one. Hints are still capped at
+// MaxDelay so a misbehaving server cannot stall the REPL indefinitely.
+func (p RetryPolicy) wait(retry int, retryAfter time.Duration) time.Duration {
+	if retryAfter <= 0 {
+		return p.backoff(retry)
+	}
+	if p.MaxDelay > 0 && retryAfter > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return retryAfter
+}
+
+// isRetryable reports whether err is worth another attempt: a 429 or 5xx
+// response, or a network failure such as a timeout, a refused or reset
+// connection, a connection closed before the response arrived, or a body
+// cut short. Every request is an idempotent GET, so repeating one is safe.
+// Anything else, like a malformed URL, a
+// certificate error or a missing snapshot file, would fail the same way
+// again.
+func isRetryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrServer)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// parseRetryAfter understands both forms of the Retry-After header: a
+// number of seconds or an HTTP date. It is only consulted for 429 and 503
+// responses, where the header is meaningful.
+func parseRetryAfter(res *http.Response) time.Duration {
+	if res.StatusCode != http.StatusTooManyRequests && res.StatusCode != http.StatusServiceUnavailable {
+		return 0
+	}
+
+	header := res.Header.Get("Retry-After")
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
@@ -0,0 +1,158 @@
+package pokeapi
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// failingServer answers the first failures requests with status and
+// succeeds afterwards, counting every request it receives.
+func failingServer(failures int, status int, retryAfter string) (*httptest.Server, *int) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+		fmt.Fprint(w, `{"name": "pikachu"}`)
+	}))
+	return server, &requests
+}
+
+func TestRetry(t *testing.T) {
+	fast := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+	cases := []struct {
+		name             string
+		failures         int
+		status           int
+		expectedErr      error
+		expectedRequests int
+	}{
+		{name: "recovers from server errors", failures: 2, status: http.StatusBadGateway, expectedRequests: 3},
+		{name: "recovers from rate limiting", failures: 1, status: http.StatusTooManyRequests, expectedRequests: 2},
+		{name: "gives up after max attempts", failures: 5, status: http.StatusServiceUnavailable, expectedErr: ErrServer, expectedRequests: 3},
+		{name: "does not retry not found", failures: 5, status: http.StatusNotFound, expectedErr: ErrNotFound, expectedRequests: 1},
+	}
+
+	for _, c := range cases {
+		server, requests := failingServer(c.failures, c.status, "")
+		client := NewClient(server.URL, server.Client(), nil)
+		client.SetRetryPolicy(fast)
+
+		_, err := client.GetPokemon(context.Background(), "pikachu")
+		server.Close()
+
+		if c.expectedErr == nil && err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+		}
+		if c.expectedErr != nil && !errors.Is(err, c.expectedErr) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expectedErr, err)
+		}
+		if *requests != c.expectedRequests {
+			t.Errorf("%s: expected %d requests, got %d", c.name, c.expectedRequests, *requests)
+		}
+	}
+}
+
+// failingTransport fails every request with err, counting them.
+type failingTransport struct {
+	err      error
+	requests int
+}
+
+func (t *failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	t.requests++
+	return nil, t.err
+}
+
+func TestRetryTransportErrors(t *testing.T) {
+	fast := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+	cases := []struct {
+		name             string
+		err              error
+		expectedRequests int
+	}{
+		{name: "connection refused", err: &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, expectedRequests: 3},
+		{name: "connection reset", err: &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, expectedRequests: 3},
+		{name: "timeout", err: &net.DNSError{Err: "i/o timeout", Name: "pokeapi.co", IsTimeout: true}, expectedRequests: 3},
+		{name: "connection closed", err: io.EOF, expectedRequests: 3},
+		{name: "truncated response", err: io.ErrUnexpectedEOF, expectedRequests: 3},
+		{name: "certificate error", err: x509.UnknownAuthorityError{}, expectedRequests: 1},
+		{name: "missing snapshot", err: fmt.Errorf("reading snapshot: %w", fs.ErrNotExist), expectedRequests: 1},
+		{name: "unsupported scheme", err: errors.New(`unsupported protocol scheme ""`), expectedRequests: 1},
+	}
+
+	for _, c := range cases {
+		transport := &failingTransport{err: c.err}
+		client := NewClient("https://pokeapi.co/api/v2", &http.Client{Transport: transport}, nil)
+		client.SetRetryPolicy(fast)
+
+		if _, err := client.GetPokemon(context.Background(), "pikachu"); err == nil {
+			t.Errorf("%s: expected an error", c.name)
+		}
+		if transport.requests != c.expectedRequests {
+			t.Errorf("%s: expected %d requests, got %d", c.name, c.expectedRequests, transport.requests)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	server, requests := failingServer(1, http.StatusTooManyRequests, "1")
+	defer server.Close()
+
+	client := NewClient(server.URL, server.Client(), nil)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Second})
+
+	start := time.Now()
+	if _, err := client.GetPokemon(context.Background(), "pikachu"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected to wait for Retry-After, only waited %v", elapsed)
+	}
+	if *requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", *requests)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	cases := []struct {
+		retry int
+		min   time.Duration
+		max   time.Duration
+	}{
+		{retry: 1, min: 50 * time.Millisecond, max: 100 * time.Millisecond},
+		{retry: 2, min: 100 * time.Millisecond, max: 200 * time.Millisecond},
+		{retry: 3, min: 200 * time.Millisecond, max: 400 * time.Millisecond},
+		{retry: 8, min: 500 * time.Millisecond, max: time.Second},
+	}
+
+	for _, c := range cases {
+		for i := 0; i < 20; i++ {
+			delay := policy.backoff(c.retry)
+			if delay < c.min || delay > c.max {
+				t.Errorf("Expected backoff for retry %d within [%v, %v], got %v", c.retry, c.min, c.max, delay)
+			}
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package pokeapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const defaultPageSize = 20
+
+// SnapshotTransport is an http.RoundTripper that answers requests from a
+// local directory laid out like PokeAPI's api-data repository, where
+// /api/v2/pokemon/pikachu/ is stored at api/v2/pokemon/pikachu/index.json.
+// List endpoints are stored as a single index.json holding every result,
+// and pages are sliced out of it using the offset and limit parameters.
+// api-data itself stores resources by ID, as api/v2/pokemon/25/index.json,
+// so a resource missing under its name is found through its endpoint's
+// list instead.
+type SnapshotTransport struct {
+	fsys fs.FS
+}
+
+func NewSnapshotTransport(dir string) *SnapshotTransport {
+	return NewSnapshotTransportFS(os.DirFS(dir))
+}
+
+// NewSnapshotTransportFS is like NewSnapshotTransport but reads the snapshot
+// from fsys, such as an embedded set of test fixtures.
+func NewSnapshotTransportFS(fsys fs.FS) *SnapshotTransport {
+	return &SnapshotTransport{fsys: fsys}
+}
+
+func (t *SnapshotTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The escaped path keeps an escaped slash inside a name from reaching
+	// another file.
+	data, err := t.read(req.URL.EscapedPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return snapshotResponse(req, http.StatusNotFound, []byte("Not Found")), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list Response
+	if json.Unmarshal(data, &list) == nil && list.Results != nil {
+		data, err = json.Marshal(paginate(req.URL, list))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return snapshotResponse(req, http.StatusOK, data), nil
+}
+
+// read returns the file holding the response for urlPath. When there is no
+// file for a resource's name, the list of its endpoint is searched for the
+// name and the file stored under the ID in its URL is read instead.
+func (t *SnapshotTransport) read(urlPath string) ([]byte, error) {
+	data, err := fs.ReadFile(t.fsys, snapshotName(urlPath))
+	if !errors.Is(err, fs.ErrNotExist) {
+		return data, err
+	}
+
+	endpoint, name := path.Split(path.Clean("/" + urlPath))
+	if _, isID := strconv.Atoi(name); name == "" || isID == nil {
+		return nil, err
+	}
+	listData, listErr := fs.ReadFile(t.fsys, snapshotName(endpoint))
+	var list Response
+	if listErr != nil || json.Unmarshal(listData, &list) != nil {
+		return nil, err
+	}
+	for _, result := range list.Results {
+		if result.Name == name {
+			return fs.ReadFile(t.fsys, snapshotName(endpoint+path.Base(result.URL)))
+		}
+	}
+	return nil, err
+}
+
+// snapshotName returns the slash-separated name of the file holding the
+// response for urlPath, relative to the root of a snapshot.
+func snapshotName(urlPath string) string {
+	return path.Join(strings.Trim(path.Clean("/"+urlPath), "/"), "index.json")
+}
+
+func snapshotFile(dir string, urlPath string) string {
+	return filepath.Join(dir, filepath.FromSlash(snapshotName(urlPath)))
+}
+
+func snapshotResponse(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// paginate slices the page requested by u out of a full list, pointing the
+// Next and Previous links back at the same endpoint like PokeAPI does.
+func paginate(u *url.URL, list Response) Response {
+	query := u.Query()
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultPageSize
+	}
+	offset = min(max(offset, 0), len(list.Results))
+	end := min(offset+limit, len(list.Results))
+
+	pageURL := func(offset int) string {
+		next := *u
+		next.RawQuery = url.Values{
+			"offset": {strconv.Itoa(offset)},
+			"limit":  {strconv.Itoa(limit)},
+		}.Encode()
+		return next.String()
+	}
+
+	page := Response{
+		Count:   len(list.Results),
+		Results: list.Results[offset:end],
+	}
+	if end < len(list.Results) {
+		page.Next = pageURL(end)
+	}
+	if offset > 0 {
+		previous := pageURL(max(offset-limit, 0))
+		page.Previous = &previous
+	}
+
+	return page
+}
+
+// Snapshot crawls every endpoint the CLI uses into dir, in the layout read
+// by SnapshotTransport: all location-area pages, each area, and every
+// Pokemon encountered in those areas. A positive maxAreas stops the crawl
+// after that many areas. Files that already exist are not fetched again, so
+// an interrupted snapshot can be resumed. progress, if not nil, is called
+// with the API path of each file written.
+func (c *Client) Snapshot(ctx context.Context, dir string, maxAreas int, progress func(string)) error {
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return err
+	}
+
+	save := func(apiPath string, data []byte) error {
+		file := snapshotFile(dir, apiPath)
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, data, 0o644); err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(apiPath)
+		}
+		return nil
+	}
+	// fetch decodes the response for endpoint, saving it to the snapshot
+	// unless it is already there.
+	fetch := func(endpoint string, decode func([]byte) error) error {
+		if data, err := os.ReadFile(snapshotFile(dir, base.Path+endpoint)); err == nil {
+			return decode(data)
+		}
+		body, err := c.getRaw(ctx, c.baseURL+endpoint, decode)
+		if err != nil {
+			return err
+		}
+		return save(base.Path+endpoint, body)
+	}
+
+	var areas []Results
+	pageURL := ""
+	for {
+		page, err := c.ListLocationAreas(ctx, pageURL)
+		if err != nil {
+			return err
+		}
+		areas = append(areas, page.Results...)
+		if page.Next == "" || (maxAreas > 0 && len(areas) >= maxAreas) {
+			break
+		}
+		pageURL = page.Next
+	}
+	if maxAreas > 0 && len(areas) > maxAreas {
+		areas = areas[:maxAreas]
+	}
+
+	list, err := json.Marshal(Response{Count: len(areas), Results: areas})
+	if err != nil {
+		return err
+	}
+	if err := save(base.Path+locationAreaPath, list); err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	for _, area := range areas {
+		var areaData ExploreResponse
+		err := fetch(locationAreaPath+area.Name, func(body []byte) error {
+			return json.Unmarshal(body, &areaData)
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, encounter := range areaData.PokemonEncounters {
+			name := encounter.Pokemon.Name
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			err := fetch(pokemonPath+name, func(body []byte) error {
+				return json.Unmarshal(body, &Pokemon{})
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
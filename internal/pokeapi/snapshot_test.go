@@ -0,0 +1,131 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func snapshotServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/location-area/", func(w http.ResponseWriter, r *http.Request) {
+		base := "http://" + r.Host + "/api/v2/location-area/"
+		if r.URL.Query().Get("offset") == "2" {
+			fmt.Fprintf(w, `{"count": 3, "next": null, "previous": "%s", "results": [{"name": "route-3"}]}`, base)
+			return
+		}
+		fmt.Fprintf(w, `{"count": 3, "next": "%s?offset=2&limit=2", "previous": null, "results": [{"name": "route-1"}, {"name": "route-2"}]}`, base)
+	})
+	mux.HandleFunc("/api/v2/location-area/{name}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"pokemon_encounters": [{"pokemon": {"name": "pidgey"}}, {"pokemon": {"name": "%s-mon"}}]}`, r.PathValue("name"))
+	})
+	mux.HandleFunc("/api/v2/pokemon/{name}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"name": "%s"}`, r.PathValue("name"))
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestSnapshot(t *testing.T) {
+	server := snapshotServer()
+	defer server.Close()
+
+	dir := t.TempDir()
+	online := NewClient(server.URL+"/api/v2", server.Client(), nil)
+
+	written := 0
+	err := online.Snapshot(context.Background(), dir, 0, func(string) { written++ })
+	if err != nil {
+		t.Fatalf("Unexpected error taking snapshot: %v", err)
+	}
+	// One list, three areas, pidgey and one Pokemon unique to each area.
+	if written != 8 {
+		t.Errorf("Expected 8 files to be written, got %d", written)
+	}
+
+	offline := NewClient(BaseURL, &http.Client{Transport: NewSnapshotTransport(dir)}, nil)
+	ctx := context.Background()
+
+	page, err := offline.ListLocationAreas(ctx, BaseURL+"/location-area/?offset=0&limit=2")
+	if err != nil {
+		t.Fatalf("Unexpected error listing areas offline: %v", err)
+	}
+	if len(page.Results) != 2 || page.Next == "" || page.Previous != nil {
+		t.Fatalf("Unexpected first page %+v", page)
+	}
+
+	page, err = offline.ListLocationAreas(ctx, page.Next)
+	if err != nil {
+		t.Fatalf("Unexpected error following next link offline: %v", err)
+	}
+	if len(page.Results) != 1 || page.Results[0].Name != "route-3" || page.Next != "" || page.Previous == nil {
+		t.Errorf("Unexpected second page %+v", page)
+	}
+
+	area, err := offline.GetLocationArea(ctx, "route-2")
+	if err != nil || len(area.PokemonEncounters) != 2 {
+		t.Errorf("Expected route-2 from snapshot, got %+v (%v)", area, err)
+	}
+
+	pokemon, err := offline.GetPokemon(ctx, "route-2-mon")
+	if err != nil || pokemon.Name != "route-2-mon" {
+		t.Errorf("Expected route-2-mon from snapshot, got %+v (%v)", pokemon, err)
+	}
+
+	if _, err := offline.GetPokemon(ctx, "mewtwo"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for Pokemon missing from snapshot, got %v", err)
+	}
+}
+
+func TestSnapshotMaxAreas(t *testing.T) {
+	server := snapshotServer()
+	defer server.Close()
+
+	dir := t.TempDir()
+	online := NewClient(server.URL+"/api/v2", server.Client(), nil)
+	if err := online.Snapshot(context.Background(), dir, 1, nil); err != nil {
+		t.Fatalf("Unexpected error taking snapshot: %v", err)
+	}
+
+	offline := NewClient(BaseURL, &http.Client{Transport: NewSnapshotTransport(dir)}, nil)
+	page, err := offline.ListLocationAreas(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Results) != 1 || page.Count != 1 {
+		t.Errorf("Expected only one area in snapshot, got %+v", page)
+	}
+	if _, err := offline.GetLocationArea(context.Background(), "route-2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected route-2 to be excluded from snapshot, got %v", err)
+	}
+}
+
+func TestSnapshotByID(t *testing.T) {
+	// An api-data checkout stores resources by ID, with relative URLs in its
+	// lists.
+	fsys := fstest.MapFS{
+		"api/v2/pokemon/index.json": {Data: []byte(`{"count": 2, "results": [
+			{"name": "bulbasaur", "url": "/api/v2/pokemon/1/"},
+			{"name": "pikachu", "url": "/api/v2/pokemon/25/"}
+		]}`)},
+		"api/v2/pokemon/25/index.json": {Data: []byte(`{"id": 25, "name": "pikachu"}`)},
+	}
+	client := NewClient(BaseURL, &http.Client{Transport: NewSnapshotTransportFS(fsys)}, nil)
+	ctx := context.Background()
+
+	for _, name := range []string{"pikachu", "25"} {
+		pokemon, err := client.GetPokemon(ctx, name)
+		if err != nil || pokemon.ID != 25 {
+			t.Errorf("Expected pikachu for %s, got %+v (%v)", name, pokemon, err)
+		}
+	}
+	if _, err := client.GetPokemon(ctx, "bulbasaur"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a listed Pokemon without a file, got %v", err)
+	}
+	if _, err := client.GetPokemon(ctx, "mewtwo"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unlisted Pokemon, got %v", err)
+	}
+}
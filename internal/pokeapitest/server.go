@@ -0,0 +1,91 @@
+// Package pokeapitest provides a fake PokeAPI server that serves canned
+// location areas and Pokemon, so the CLI can be tested without reaching
+// pokeapi.co.
+package pokeapitest
+
+import (
+	"bytes"
+	"embed"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/roninii/pokedexcli/internal/pokeapi"
+)
+
+// fixtures is a snapshot in the layout read by pokeapi.SnapshotTransport,
+// with resources stored under their names. The URLs inside refer to
+// pokeapi.co and are rewritten to point at the server on the fly.
+//
+//go:embed testdata
+var fixtures embed.FS
+
+type Server struct {
+	*httptest.Server
+
+	transport *pokeapi.SnapshotTransport
+	mu        sync.Mutex
+	requests  []string
+}
+
+// NewServer starts a fake PokeAPI server. Callers should Close it when done.
+func NewServer() *Server {
+	root, err := fs.Sub(fixtures, "testdata")
+	if err != nil {
+		panic(err)
+	}
+
+	s := &Server{transport: pokeapi.NewSnapshotTransportFS(root)}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// BaseURL is the equivalent of pokeapi.BaseURL for this server.
+func (s *Server) BaseURL() string {
+	return s.URL + "/api/v2"
+}
+
+// NewClient returns an uncached client pointed at the server.
+func (s *Server) NewClient() *pokeapi.Client {
+	return pokeapi.NewClient(s.BaseURL(), s.Client(), nil)
+}
+
+// Requests returns the request URIs the server has received, in order.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL.RequestURI())
+	s.mu.Unlock()
+
+	// Give the transport an absolute URL so pagination links point back at
+	// this server.
+	req := r.Clone(r.Context())
+	req.URL.Scheme = "http"
+	req.URL.Host = r.Host
+
+	res, err := s.transport.RoundTrip(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	body = bytes.ReplaceAll(body, []byte(pokeapi.BaseURL), []byte(s.BaseURL()))
+
+	w.Header().Set("Content-Type", res.Header.Get("Content-Type"))
+	w.WriteHeader(res.StatusCode)
+	w.Write(body)
+}
@@ -0,0 +1,44 @@
+package pokeapitest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/roninii/pokedexcli/internal/pokeapi"
+)
+
+func TestServer(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client := server.NewClient()
+	ctx := context.Background()
+
+	page, err := client.ListLocationAreas(ctx, "")
+	if err != nil {
+		t.Fatalf("Unexpected error listing areas: %v", err)
+	}
+	if len(page.Results) != 20 {
+		t.Errorf("Expected a full page of 20 areas, got %d", len(page.Results))
+	}
+	if !strings.HasPrefix(page.Next, server.BaseURL()) {
+		t.Errorf("Expected next link to point at the server, got %s", page.Next)
+	}
+	if !strings.HasPrefix(page.Results[0].URL, server.BaseURL()) {
+		t.Errorf("Expected result URLs to point at the server, got %s", page.Results[0].URL)
+	}
+
+	if _, err := client.GetPokemon(ctx, "pikachu"); err != nil {
+		t.Errorf("Unexpected error fetching pikachu: %v", err)
+	}
+	if _, err := client.GetPokemon(ctx, "missingno"); !errors.Is(err, pokeapi.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unknown Pokemon, got %v", err)
+	}
+
+	expected := []string{"/api/v2/location-area/", "/api/v2/pokemon/pikachu", "/api/v2/pokemon/missingno"}
+	if requests := server.Requests(); strings.Join(requests, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected requests %v, got %v", expected, requests)
+	}
+}
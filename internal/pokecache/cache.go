@@ -1,57 +1,179 @@
 package pokecache
 
 import (
+	"container/list"
 	"sync"
 	"time"
 )
 
-type Cache map[string]cacheEntry
+// Limits bounds the size of a cache. A zero value for either field means
+// no limit of that kind.
+type Limits struct {
+	MaxEntries int
+	MaxBytes   int
+}
+
+// Stats is a snapshot of a cache's counters.
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int
+	Bytes     int
+}
+
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// order holds entries from most to least recently used.
+	order  *list.List
+	limits Limits
+	bytes  int
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
 
-var mux sync.RWMutex
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
 
 type cacheEntry struct {
+	key       string
 	createdAt time.Time
 	val       []byte
 }
 
-func NewCache(interval time.Duration) Cache {
-	cache := Cache{}
-	go cache.ReadLoop(interval)
+func (e *cacheEntry) size() int {
+	return len(e.key) + len(e.val)
+}
+
+// NewCache returns a cache whose entries expire after interval. It starts a
+// goroutine to reap expired entries, which runs until Close is called.
+func NewCache(interval time.Duration) *Cache {
+	return NewBoundedCache(interval, Limits{})
+}
+
+// NewBoundedCache is like NewCache, but additionally evicts the least
+// recently used entries whenever adding one would exceed limits.
+func NewBoundedCache(interval time.Duration, limits Limits) *Cache {
+	cache := &Cache{
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+		limits:  limits,
+		done:    make(chan struct{}),
+	}
+
+	cache.wg.Add(1)
+	go cache.readLoop(interval)
+
 	return cache
 }
 
 func (c *Cache) Add(key string, val []byte) {
-	mux.Lock()
-	defer mux.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
 
-	(*c)[key] = cacheEntry{
+	entry := &cacheEntry{
+		key:       key,
 		createdAt: time.Now(),
 		val:       val,
 	}
+	// An entry that could never fit would just flush everything else out.
+	if c.limits.MaxBytes > 0 && entry.size() > c.limits.MaxBytes {
+		c.evictions++
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	c.bytes += entry.size()
+
+	for c.overLimits() {
+		c.remove(c.order.Back())
+		c.evictions++
+	}
 }
 
 func (c *Cache) Get(key string) ([]byte, bool) {
-	mux.RLock()
-	defer mux.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	entry, ok := (*c)[key]
+	elem, ok := c.entries[key]
 	if !ok {
+		c.misses++
 		return nil, false
 	}
 
-	return entry.val, true
+	c.hits++
+	c.order.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).val, true
 }
 
-func (c *Cache) ReadLoop(interval time.Duration) {
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return Stats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Entries:   len(c.entries),
+		Bytes:     c.bytes,
+	}
+}
+
+// Close stops the reaping goroutine and waits for it to exit. The cache can
+// still be read and written afterwards, but entries no longer expire. It is
+// safe to call Close more than once.
+func (c *Cache) Close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+	c.wg.Wait()
+}
+
+func (c *Cache) overLimits() bool {
+	if c.limits.MaxEntries > 0 && len(c.entries) > c.limits.MaxEntries {
+		return true
+	}
+	return c.limits.MaxBytes > 0 && c.bytes > c.limits.MaxBytes
+}
+
+// remove must be called with c.mu held.
+func (c *Cache) remove(elem *list.Element) {
+	entry := c.order.Remove(elem).(*cacheEntry)
+	delete(c.entries, entry.key)
+	c.bytes -= entry.size()
+}
+
+func (c *Cache) readLoop(interval time.Duration) {
+	defer c.wg.Done()
+
 	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
 	for {
-		<-ticker.C
-		mux.Lock()
-		for k, v := range *c {
-			if time.Since(v.createdAt) > interval {
-				delete(*c, k)
-			}
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			c.reap(interval)
+		}
+	}
+}
+
+func (c *Cache) reap(interval time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, elem := range c.entries {
+		if time.Since(elem.Value.(*cacheEntry).createdAt) > interval {
+			c.remove(elem)
 		}
-		mux.Unlock()
 	}
 }
@@ -1,6 +1,8 @@
 package pokecache
 
 import (
+	"runtime"
+	"sync"
 	"testing"
 	"time"
 )
@@ -18,6 +20,7 @@ func TestAdd(t *testing.T) {
 
 	for _, c := range cases {
 		cache := NewCache(5 * time.Second)
+		defer cache.Close()
 		cache.Add(c.key, c.value)
 
 		if _, exists := cache.Get(c.key); !exists {
@@ -44,6 +47,7 @@ func TestReadLoop(t *testing.T) {
 	for _, c := range cases {
 		interval := 5 * time.Second
 		cache := NewCache(interval)
+		defer cache.Close()
 
 		cache.Add(c.key, c.value)
 
@@ -54,3 +58,115 @@ func TestReadLoop(t *testing.T) {
 		}
 	}
 }
+
+func TestClose(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	for i := 0; i < 10; i++ {
+		cache := NewCache(time.Millisecond)
+		cache.Add("key", []byte("value"))
+		cache.Close()
+		cache.Close()
+	}
+
+	// Goroutines are torn down asynchronously by the runtime, so give them
+	// a moment before declaring a leak.
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("Expected no leaked goroutines, had %d before and %d after", before, after)
+	}
+}
+
+func TestIndependentCaches(t *testing.T) {
+	first := NewCache(5 * time.Second)
+	defer first.Close()
+	second := NewCache(5 * time.Second)
+	defer second.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			first.Add("key", []byte("first"))
+		}()
+		go func() {
+			defer wg.Done()
+			second.Get("key")
+		}()
+	}
+	wg.Wait()
+
+	if _, exists := second.Get("key"); exists {
+		t.Errorf("Expected entries added to one cache not to appear in another")
+	}
+}
+
+func TestEviction(t *testing.T) {
+	cases := []struct {
+		name     string
+		limits   Limits
+		kept     []string
+		evicted  []string
+		expected uint64
+	}{
+		{
+			name:     "max entries",
+			limits:   Limits{MaxEntries: 2},
+			kept:     []string{"a", "c"},
+			evicted:  []string{"b"},
+			expected: 1,
+		},
+		{
+			// Each entry is 1 byte of key plus 4 bytes of value.
+			name:     "max bytes",
+			limits:   Limits{MaxBytes: 12},
+			kept:     []string{"a", "c"},
+			evicted:  []string{"b"},
+			expected: 1,
+		},
+	}
+
+	for _, c := range cases {
+		cache := NewBoundedCache(5*time.Second, c.limits)
+		cache.Add("a", []byte("aaaa"))
+		cache.Add("b", []byte("bbbb"))
+		// Touch a so that b becomes the least recently used entry.
+		cache.Get("a")
+		cache.Add("c", []byte("cccc"))
+
+		for _, key := range c.kept {
+			if _, exists := cache.Get(key); !exists {
+				t.Errorf("%s: expected %s to be kept", c.name, key)
+			}
+		}
+		for _, key := range c.evicted {
+			if _, exists := cache.Get(key); exists {
+				t.Errorf("%s: expected %s to be evicted", c.name, key)
+			}
+		}
+		if stats := cache.Stats(); stats.Evictions != c.expected {
+			t.Errorf("%s: expected %d evictions, got %d", c.name, c.expected, stats.Evictions)
+		}
+		cache.Close()
+	}
+}
+
+func TestStats(t *testing.T) {
+	cache := NewBoundedCache(5*time.Second, Limits{MaxBytes: 10})
+	defer cache.Close()
+
+	cache.Add("small", []byte("data"))
+	cache.Add("too-large", []byte("this value does not fit"))
+	cache.Get("small")
+	cache.Get("too-large")
+
+	expected := Stats{Hits: 1, Misses: 1, Evictions: 1, Entries: 1, Bytes: 9}
+	if stats := cache.Stats(); stats != expected {
+		t.Errorf("Expected %+v, got %+v", expected, stats)
+	}
+}
@@ -0,0 +1,120 @@
+package pokecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/roninii/pokedexcli/internal/paths"
+)
+
+// DiskCache stores responses as files in a directory so they survive
+// restarts. Entries older than the TTL are treated as missing and removed
+// when next read.
+type DiskCache struct {
+	dir string
+	ttl time.Duration
+}
+
+type diskEntry struct {
+	Key       string    `json:"key"`
+	CreatedAt time.Time `json:"created_at"`
+	Val       []byte    `json:"val"`
+}
+
+func NewDiskCache(dir string, ttl time.Duration) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir, ttl: ttl}, nil
+}
+
+// DefaultDiskCacheDir returns a directory for the disk cache under the
+// user's cache directory.
+func DefaultDiskCacheDir() (string, error) {
+	cacheDir, err := paths.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "http"), nil
+}
+
+// Add writes val to disk. The disk cache is best-effort, so failures are
+// ignored and simply result in a later miss.
+func (d *DiskCache) Add(key string, val []byte) {
+	data, err := json.Marshal(diskEntry{
+		Key:       key,
+		CreatedAt: time.Now(),
+		Val:       val,
+	})
+	if err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(d.dir, "entry-*.tmp")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err != nil || closeErr != nil {
+		return
+	}
+	os.Rename(tmp.Name(), d.path(key))
+}
+
+func (d *DiskCache) Get(key string) ([]byte, bool) {
+	path := d.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry diskEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Key != key {
+		return nil, false
+	}
+	if d.ttl > 0 && time.Since(entry.CreatedAt) > d.ttl {
+		os.Remove(path)
+		return nil, false
+	}
+
+	return entry.Val, true
+}
+
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Tiered checks an in-memory cache before falling back to a disk cache,
+// promoting disk hits into memory so they stay fast for the session.
+type Tiered struct {
+	memory *Cache
+	disk   *DiskCache
+}
+
+func NewTiered(memory *Cache, disk *DiskCache) *Tiered {
+	return &Tiered{memory: memory, disk: disk}
+}
+
+func (t *Tiered) Add(key string, val []byte) {
+	t.memory.Add(key, val)
+	t.disk.Add(key, val)
+}
+
+func (t *Tiered) Get(key string) ([]byte, bool) {
+	if val, ok := t.memory.Get(key); ok {
+		return val, true
+	}
+
+	val, ok := t.disk.Get(key)
+	if ok {
+		t.memory.Add(key, val)
+	}
+	return val, ok
+}
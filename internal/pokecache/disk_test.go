@@ -0,0 +1,63 @@
+package pokecache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiskCache(t *testing.T) {
+	dir := t.TempDir()
+	key := "https://pokeapi.co/api/v2/location-area/"
+
+	cache, err := NewDiskCache(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Add(key, []byte("testdata"))
+
+	// A fresh instance stands in for a restarted session.
+	reopened, err := NewDiskCache(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val, exists := reopened.Get(key); !exists || string(val) != "testdata" {
+		t.Errorf("Expected testdata to survive reopening, got %q (exists: %v)", val, exists)
+	}
+	if _, exists := reopened.Get(key + "?offset=20"); exists {
+		t.Errorf("Expected a miss for an unknown key")
+	}
+}
+
+func TestDiskCacheTTL(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir(), 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Add("key", []byte("value"))
+
+	time.Sleep(20 * time.Millisecond)
+
+	if _, exists := cache.Get("key"); exists {
+		t.Errorf("Expected expired entry to be missing")
+	}
+}
+
+func TestTiered(t *testing.T) {
+	dir := t.TempDir()
+	disk, err := NewDiskCache(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	disk.Add("key", []byte("value"))
+
+	memory := NewCache(5 * time.Second)
+	defer memory.Close()
+	tiered := NewTiered(memory, disk)
+
+	if val, exists := tiered.Get("key"); !exists || string(val) != "value" {
+		t.Fatalf("Expected disk entry through tiered cache, got %q (exists: %v)", val, exists)
+	}
+	if _, exists := memory.Get("key"); !exists {
+		t.Errorf("Expected disk hit to be promoted into memory")
+	}
+}
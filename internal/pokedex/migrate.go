@@ -0,0 +1,133 @@
+package pokedex
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Migration upgrades the raw JSON of a save file by exactly one schema
+// version. It is responsible for bumping the version field as well.
+type Migration func(data []byte) ([]byte, error)
+
+// migrations is keyed by the version a migration upgrades from. When the
+// save format changes, bump saveVersion and register a migration for the
+// previous version here.
+var migrations = map[int]Migration{
+	1: migrateSpecimens,
+	2: migrateSeen,
+}
+
+type saveHeader struct {
+	Version int `json:"version"`
+}
+
+func readVersion(data []byte) (int, error) {
+	var header saveHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return 0, err
+	}
+	return header.Version, nil
+}
+
+// migrate upgrades data to saveVersion, first backing up the original file
+// next to path so a failed or buggy migration never costs the player their
+// collection.
+func migrate(path string, data []byte) ([]byte, error) {
+	version, err := readVersion(data)
+	if err != nil {
+		return nil, fmt.Errorf("reading save file version: %w", err)
+	}
+	if version == saveVersion {
+		return data, nil
+	}
+	if version > saveVersion {
+		return nil, fmt.Errorf("save file version %d is newer than this version of the Pokedex supports (%d)", version, saveVersion)
+	}
+
+	backupPath := fmt.Sprintf("%s.v%d.bak", path, version)
+	if err := writeFileAtomic(backupPath, data); err != nil {
+		return nil, fmt.Errorf("backing up save file before migrating: %w", err)
+	}
+
+	for v := version; v < saveVersion; v++ {
+		migration, ok := migrations[v]
+		if !ok {
+			return nil, fmt.Errorf("no migration from save file version %d", v)
+		}
+
+		data, err = migration(data)
+		if err != nil {
+			return nil, fmt.Errorf("migrating save file from version %d: %w", v, err)
+		}
+
+		if got, err := readVersion(data); err != nil || got != v+1 {
+			return nil, fmt.Errorf("migration from version %d produced version %d", v, got)
+		}
+	}
+
+	return data, nil
+}
+
+// migrateSpecimens upgrades version 1, which kept one entry per species,
+// to version 2, which records each caught specimen. Every species becomes
+// a single specimen whose catch time and location are unknown.
+func migrateSpecimens(data []byte) ([]byte, error) {
+	var v1 struct {
+		Pokemon map[string]Pokemon `json:"pokemon"`
+	}
+	if err := json.Unmarshal(data, &v1); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(v1.Pokemon))
+	for name := range v1.Pokemon {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Spelled out rather than using saveFile, which will keep changing.
+	v2 := struct {
+		Version   int                `json:"version"`
+		Species   map[string]Pokemon `json:"species"`
+		Specimens []Specimen         `json:"specimens"`
+	}{
+		Version:   2,
+		Species:   map[string]Pokemon{},
+		Specimens: []Specimen{},
+	}
+	for i, name := range names {
+		p := v1.Pokemon[name]
+		v2.Species[p.Name] = p
+		v2.Specimens = append(v2.Specimens, Specimen{ID: i + 1, Species: p.Name})
+	}
+
+	return json.Marshal(v2)
+}
+
+// migrateSeen upgrades version 2 to version 3, which also records the
+// Pokemon that have been seen. Only caught species are known to have been.
+func migrateSeen(data []byte) ([]byte, error) {
+	var save map[string]json.RawMessage
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, err
+	}
+	var species map[string]Pokemon
+	if raw, ok := save["species"]; ok {
+		if err := json.Unmarshal(raw, &species); err != nil {
+			return nil, err
+		}
+	}
+
+	seen := map[string]int{}
+	for name, p := range species {
+		seen[name] = p.ID
+	}
+
+	var err error
+	if save["seen"], err = json.Marshal(seen); err != nil {
+		return nil, err
+	}
+	save["version"] = json.RawMessage("3")
+	return json.Marshal(save)
+}
@@ -0,0 +1,116 @@
+package pokedex
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	// Pretend version 0 stored the Pokemon map at the top level.
+	migrations[0] = func(data []byte) ([]byte, error) {
+		var legacy map[string]json.RawMessage
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]any{
+			"version": 1,
+			"pokemon": legacy["caught"],
+		})
+	}
+	defer delete(migrations, 0)
+
+	path := filepath.Join(t.TempDir(), "pokedex.json")
+	original := []byte(`{"version": 0, "caught": {"pidgey": {"id": 16, "name": "pidgey"}}}`)
+	if err := os.WriteFile(path, original, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	Reset()
+	if err := Load(path); err != nil {
+		t.Fatalf("Unexpected error loading: %v", err)
+	}
+
+	if p, ok := Pokedex["pidgey"]; !ok || p.ID != 16 {
+		t.Errorf("Expected pidgey to be migrated, got %+v", Pokedex)
+	}
+	if len(Specimens) != 1 || Specimens[0].Species != "pidgey" {
+		t.Errorf("Expected a pidgey specimen after migrating, got %+v", Specimens)
+	}
+
+	backup, err := os.ReadFile(path + ".v0.bak")
+	if err != nil {
+		t.Fatalf("Expected a backup of the original save: %v", err)
+	}
+	if string(backup) != string(original) {
+		t.Errorf("Expected backup to match original save, got %s", backup)
+	}
+}
+
+func TestMigrateSpecimens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pokedex.json")
+	v1 := `{"version": 1, "pokemon": {"rattata": {"id": 19, "name": "rattata"}, "pidgey": {"id": 16, "name": "pidgey"}}}`
+	if err := os.WriteFile(path, []byte(v1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	Reset()
+	if err := Load(path); err != nil {
+		t.Fatalf("Unexpected error loading: %v", err)
+	}
+
+	expected := []Specimen{{ID: 1, Species: "pidgey"}, {ID: 2, Species: "rattata"}}
+	if !slices.Equal(Specimens, expected) {
+		t.Errorf("Expected specimens %+v, got %+v", expected, Specimens)
+	}
+	if p := Pokedex["rattata"]; p.ID != 19 {
+		t.Errorf("Expected rattata's species data to be kept, got %+v", p)
+	}
+	if s := Catch(Pokemon{Name: "pidgey"}, Specimen{}); s.ID != 3 {
+		t.Errorf("Expected the next catch to get ID 3, got %d", s.ID)
+	}
+}
+
+func TestMigrateErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+	}{
+		{name: "newer version", data: `{"version": 99, "pokemon": {}}`},
+		{name: "missing migration", data: `{"version": -1, "pokemon": {}}`},
+		{name: "not json", data: `Not Found`},
+	}
+
+	for _, c := range cases {
+		path := filepath.Join(t.TempDir(), "pokedex.json")
+		if err := os.WriteFile(path, []byte(c.data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := Load(path); err == nil {
+			t.Errorf("%s: expected an error loading %s", c.name, c.data)
+		}
+	}
+}
+
+func TestMigrateSeen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pokedex.json")
+	v2 := `{"version": 2, "species": {"pidgey": {"id": 16, "name": "pidgey"}}, "specimens": [{"id": 1, "species": "pidgey"}]}`
+	if err := os.WriteFile(path, []byte(v2), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	Reset()
+	if err := Load(path); err != nil {
+		t.Fatalf("Unexpected error loading: %v", err)
+	}
+
+	if len(Seen) != 1 || Seen["pidgey"] != 16 {
+		t.Errorf("Expected caught species to be marked seen, got %v", Seen)
+	}
+	if len(Specimens) != 1 {
+		t.Errorf("Expected specimens to be kept, got %+v", Specimens)
+	}
+}
@@ -1,13 +1,107 @@
 package pokedex
 
 import (
+	"sort"
+	"time"
+
 	"github.com/roninii/pokedexcli/internal/pokeapi"
 )
 
 type Pokemon = pokeapi.Pokemon
 
+// Specimen is one individual caught Pokemon. Catching the same species
+// twice gives two specimens with their own IDs.
+type Specimen struct {
+	ID       int    `json:"id"`
+	Species  string `json:"species"`
+	Nickname string `json:"nickname,omitempty"`
+	// CaughtAt is nil for specimens migrated from saves that did not
+	// record it.
+	CaughtAt *time.Time `json:"caught_at,omitempty"`
+	Location string     `json:"location,omitempty"`
+	Level    int        `json:"level,omitempty"`
+}
+
+// Name returns the specimen's nickname, or its species if it has none.
+func (s Specimen) Name() string {
+	if s.Nickname != "" {
+		return s.Nickname
+	}
+	return s.Species
+}
+
+// Specimens holds every caught Pokemon in the order they were caught.
+var Specimens []Specimen
+
+// Pokedex is the caught species view of Specimens: the PokeAPI data for
+// every species with at least one specimen, keyed by name.
 var Pokedex = map[string]Pokemon{}
 
-func AddPokemon(p Pokemon) {
+// Seen maps the name of every Pokemon the player has encountered, caught or
+// not, to its national dex number, which is 0 if unknown.
+var Seen = map[string]int{}
+
+// Reset empties the collection.
+func Reset() {
+	Specimens = nil
+	Pokedex = map[string]Pokemon{}
+	Seen = map[string]int{}
+}
+
+// MarkSeen records that the player has encountered a Pokemon. An ID of 0
+// never replaces one already known.
+func MarkSeen(name string, id int) {
+	if id != 0 || Seen[name] == 0 {
+		Seen[name] = id
+	}
+}
+
+// Catch records a new specimen of p, filling in its ID and species, and
+// returns it.
+func Catch(p Pokemon, specimen Specimen) Specimen {
+	specimen.ID = nextID()
+	specimen.Species = p.Name
+	Specimens = append(Specimens, specimen)
 	Pokedex[p.Name] = p
+	MarkSeen(p.Name, p.ID)
+	return specimen
+}
+
+// FindSpecimen returns the specimen with the given ID.
+func FindSpecimen(id int) (Specimen, bool) {
+	for _, specimen := range Specimens {
+		if specimen.ID == id {
+			return specimen, true
+		}
+	}
+	return Specimen{}, false
+}
+
+// SpecimensOf returns the caught specimens of a species, oldest first.
+func SpecimensOf(species string) []Specimen {
+	var matches []Specimen
+	for _, specimen := range Specimens {
+		if specimen.Species == species {
+			matches = append(matches, specimen)
+		}
+	}
+	return matches
+}
+
+// CaughtSpecies returns the names of every caught species, sorted.
+func CaughtSpecies() []string {
+	names := make([]string, 0, len(Pokedex))
+	for name := range Pokedex {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func nextID() int {
+	id := 1
+	for _, specimen := range Specimens {
+		id = max(id, specimen.ID+1)
+	}
+	return id
 }
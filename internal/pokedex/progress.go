@@ -0,0 +1,70 @@
+package pokedex
+
+// Generation is a range of national dex numbers introduced together, along
+// with the region the games of that generation are set in.
+type Generation struct {
+	Number int
+	Region string
+	First  int
+	Last   int
+}
+
+func (g Generation) Size() int {
+	return g.Last - g.First + 1
+}
+
+func (g Generation) contains(id int) bool {
+	return id >= g.First && id <= g.Last
+}
+
+// Generations lists every generation in national dex order.
+var Generations = []Generation{
+	{Number: 1, Region: "Kanto", First: 1, Last: 151},
+	{Number: 2, Region: "Johto", First: 152, Last: 251},
+	{Number: 3, Region: "Hoenn", First: 252, Last: 386},
+	{Number: 4, Region: "Sinnoh", First: 387, Last: 493},
+	{Number: 5, Region: "Unova", First: 494, Last: 649},
+	{Number: 6, Region: "Kalos", First: 650, Last: 721},
+	{Number: 7, Region: "Alola", First: 722, Last: 809},
+	{Number: 8, Region: "Galar", First: 810, Last: 905},
+	{Number: 9, Region: "Paldea", First: 906, Last: 1025},
+}
+
+// Progress counts the distinct species seen and caught out of Total.
+type Progress struct {
+	Seen   int
+	Caught int
+	Total  int
+}
+
+// Completion reports progress through the whole national dex and through
+// each of Generations, in the same order. Pokemon whose national dex number
+// is unknown, or outside every generation such as alternate forms, are not
+// counted.
+func Completion() (national Progress, byGeneration []Progress) {
+	byGeneration = make([]Progress, len(Generations))
+	for i, g := range Generations {
+		byGeneration[i].Total = g.Size()
+		national.Total += g.Size()
+	}
+
+	count := func(id int, caught bool) {
+		for i, g := range Generations {
+			if !g.contains(id) {
+				continue
+			}
+			national.Seen++
+			byGeneration[i].Seen++
+			if caught {
+				national.Caught++
+				byGeneration[i].Caught++
+			}
+		}
+	}
+	for name, id := range Seen {
+		_, caught := Pokedex[name]
+		count(id, caught)
+	}
+
+	return national, byGeneration
+}
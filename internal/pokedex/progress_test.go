@@ -0,0 +1,35 @@
+package pokedex
+
+import "testing"
+
+func TestCompletion(t *testing.T) {
+	Reset()
+	MarkSeen("bulbasaur", 1)
+	MarkSeen("chikorita", 152)
+	MarkSeen("missingno", 0)
+	MarkSeen("pikachu-alola-cap", 10099)
+	Catch(Pokemon{ID: 25, Name: "pikachu"}, Specimen{})
+	Catch(Pokemon{ID: 25, Name: "pikachu"}, Specimen{})
+	MarkSeen("pikachu", 0)
+
+	national, byGeneration := Completion()
+	if national != (Progress{Seen: 3, Caught: 1, Total: 1025}) {
+		t.Errorf("Unexpected national progress %+v", national)
+	}
+	if byGeneration[0] != (Progress{Seen: 2, Caught: 1, Total: 151}) {
+		t.Errorf("Unexpected Kanto progress %+v", byGeneration[0])
+	}
+	if byGeneration[1] != (Progress{Seen: 1, Total: 100}) {
+		t.Errorf("Unexpected Johto progress %+v", byGeneration[1])
+	}
+}
+
+func TestGenerationsContiguous(t *testing.T) {
+	next := 1
+	for _, g := range Generations {
+		if g.First != next || g.Last < g.First {
+			t.Errorf("Generation %d covers %d-%d, expected it to start at %d", g.Number, g.First, g.Last, next)
+		}
+		next = g.Last + 1
+	}
+}
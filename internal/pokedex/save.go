@@ -0,0 +1,120 @@
+package pokedex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/roninii/pokedexcli/internal/paths"
+)
+
+// saveVersion is the schema version written by Save. Older files are
+// upgraded on load by the migrations registered in migrate.go.
+const saveVersion = 3
+
+// saveFile is the on-disk envelope. Version must stay at the top level in
+// every schema so migrate can read it before knowing the rest of the layout.
+// Species data is stored once however many specimens share it.
+type saveFile struct {
+	Version   int                `json:"version"`
+	Species   map[string]Pokemon `json:"species"`
+	Specimens []Specimen         `json:"specimens"`
+	Seen      map[string]int     `json:"seen"`
+}
+
+// DefaultSavePath returns the save file location under the user's XDG data
+// directory.
+func DefaultSavePath() (string, error) {
+	dataDir, err := paths.DataDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dataDir, "pokedex.json"), nil
+}
+
+// Load replaces the collection with the contents of the save file at path,
+// migrating it from an older schema if needed. A missing file is not an
+// error; it just means nothing has been caught yet.
+func Load(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	data, err = migrate(path, data)
+	if err != nil {
+		return fmt.Errorf("loading save file %s: %w", path, err)
+	}
+
+	var save saveFile
+	if err := json.Unmarshal(data, &save); err != nil {
+		return fmt.Errorf("decoding save file %s: %w", path, err)
+	}
+
+	Reset()
+	Specimens = save.Specimens
+	for name, id := range save.Seen {
+		Seen[name] = id
+	}
+	for _, specimen := range Specimens {
+		if p, ok := save.Species[specimen.Species]; ok {
+			Pokedex[specimen.Species] = p
+		}
+	}
+
+	return nil
+}
+
+// Save writes the collection to path. The file is written to a temporary file
+// in the same directory and renamed into place, so an interrupted save
+// never leaves a truncated file behind.
+func Save(path string) error {
+	specimens := Specimens
+	if specimens == nil {
+		specimens = []Specimen{}
+	}
+	data, err := json.Marshal(saveFile{
+		Version:   saveVersion,
+		Species:   Pokedex,
+		Specimens: specimens,
+		Seen:      Seen,
+	})
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, data)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
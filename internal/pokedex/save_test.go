@@ -0,0 +1,73 @@
+package pokedex
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "pokedex.json")
+
+	caughtAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	Reset()
+	Catch(Pokemon{ID: 25, Name: "pikachu", Height: 4}, Specimen{Nickname: "Sparky", CaughtAt: &caughtAt, Location: "eterna-forest-area", Level: 10})
+	Catch(Pokemon{ID: 16, Name: "pidgey", Weight: 18}, Specimen{Level: 3})
+	Catch(Pokemon{ID: 16, Name: "pidgey", Weight: 18}, Specimen{Level: 5})
+	MarkSeen("rattata", 19)
+
+	if err := Save(path); err != nil {
+		t.Fatalf("Unexpected error saving: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("0001-01-01")) {
+		t.Errorf("Expected unknown catch times to be left out of the save, got %s", data)
+	}
+
+	Reset()
+	if err := Load(path); err != nil {
+		t.Fatalf("Unexpected error loading: %v", err)
+	}
+
+	if len(Pokedex) != 2 || len(Specimens) != 3 {
+		t.Fatalf("Expected 2 species and 3 specimens after loading, got %d and %d", len(Pokedex), len(Specimens))
+	}
+	if p := Pokedex["pikachu"]; p.ID != 25 || p.Height != 4 {
+		t.Errorf("Expected pikachu to round trip, got %+v", p)
+	}
+	expected := Specimen{ID: 1, Species: "pikachu", Nickname: "Sparky", Location: "eterna-forest-area", Level: 10}
+	s, ok := FindSpecimen(1)
+	if !ok || s.CaughtAt == nil || !s.CaughtAt.Equal(caughtAt) {
+		t.Errorf("Expected specimen 1 to keep its catch time, got %+v", s)
+	}
+	if s.CaughtAt = nil; s != expected {
+		t.Errorf("Expected specimen 1 to round trip, got %+v", s)
+	}
+	if s, _ := FindSpecimen(2); s.CaughtAt != nil {
+		t.Errorf("Expected specimen 2 to have no catch time, got %v", *s.CaughtAt)
+	}
+	if len(Seen) != 3 || Seen["rattata"] != 19 || Seen["pikachu"] != 25 {
+		t.Errorf("Expected seen Pokemon to round trip, got %v", Seen)
+	}
+	if pidgeys := SpecimensOf("pidgey"); len(pidgeys) != 2 || pidgeys[1].ID != 3 {
+		t.Errorf("Expected two pidgey specimens, got %+v", pidgeys)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("Expected only the save file to remain, found %d entries", len(entries))
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	Reset()
+	if err := Load(filepath.Join(t.TempDir(), "missing.json")); err != nil {
+		t.Errorf("Expected missing save file to be ignored, got %v", err)
+	}
+}
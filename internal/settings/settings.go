@@ -0,0 +1,211 @@
+// Package settings holds the defaults the CLI runs with. Each setting can
+// come from, in increasing order of precedence, the built-in default, a
+// JSON config file, a POKEDEXCLI_* environment variable or a command-line
+// flag. A setting's config file key and flag share a name, such as
+// "base-url", and its environment variable is that name in upper case with
+// underscores, such as POKEDEXCLI_BASE_URL.
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/roninii/pokedexcli/internal/output"
+	"github.com/roninii/pokedexcli/internal/paths"
+	"github.com/roninii/pokedexcli/internal/pokeapi"
+)
+
+// EnvPrefix starts the name of every environment variable read by ApplyEnv.
+const EnvPrefix = "POKEDEXCLI_"
+
+// Settings are the values main passes on to the rest of the CLI. Empty
+// paths mean the default location for that file.
+type Settings struct {
+	BaseURL string        `json:"base-url"`
+	Prompt  string        `json:"prompt"`
+	Output  output.Format `json:"output"`
+
+	SavePath    string `json:"save"`
+	HistoryFile string `json:"history-file"`
+	AliasesFile string `json:"aliases-file"`
+	SnapshotDir string `json:"snapshot-dir"`
+	Offline     bool   `json:"offline"`
+
+	CacheInterval   Duration `json:"cache-interval"`
+	CacheMaxBytes   int      `json:"cache-max-bytes"`
+	CacheMaxEntries int      `json:"cache-max-entries"`
+	DiskCacheDir    string   `json:"disk-cache-dir"`
+	// DiskCacheTTL is in days; 0 disables the persistent cache.
+	DiskCacheTTL int `json:"disk-cache-ttl"`
+
+	Retries    int      `json:"retries"`
+	RetryDelay Duration `json:"retry-delay"`
+}
+
+// Duration is a time.Duration written in config files as a string such as
+// "5s" or "500ms".
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"5s\": %s", data)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Default returns the settings used when nothing overrides them.
+func Default() Settings {
+	return Settings{
+		BaseURL:       pokeapi.BaseURL,
+		Prompt:        "Pokedex > ",
+		Output:        output.Text,
+		CacheInterval: Duration(5 * time.Second),
+		CacheMaxBytes: 64 << 20,
+		DiskCacheTTL:  30,
+		Retries:       pokeapi.DefaultRetryPolicy.MaxAttempts,
+		RetryDelay:    Duration(pokeapi.DefaultRetryPolicy.BaseDelay),
+	}
+}
+
+// DefaultPath returns the config file location under the user config
+// directory.
+func DefaultPath() (string, error) {
+	configDir, err := paths.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "config.json"), nil
+}
+
+// Load returns the default settings overridden by the config file at path.
+// A missing file is not an error, but unknown keys are, to catch typos.
+// Values are checked by Validate once every override has been applied.
+func Load(path string) (Settings, error) {
+	s := Default()
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&s); err != nil {
+		return s, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// ApplyEnv overrides settings from environment variables, looked up with
+// getenv so callers can pass os.Getenv or a fake.
+func (s *Settings) ApplyEnv(getenv func(string) string) error {
+	for _, key := range Keys() {
+		if value := getenv(EnvName(key)); value != "" {
+			if err := s.Set(key, value); err != nil {
+				return fmt.Errorf("%s: %w", EnvName(key), err)
+			}
+		}
+	}
+	return nil
+}
+
+// Keys returns the name of every setting, in declaration order.
+func Keys() []string {
+	t := reflect.TypeOf(Settings{})
+	keys := make([]string, t.NumField())
+	for i := range keys {
+		keys[i] = t.Field(i).Tag.Get("json")
+	}
+	return keys
+}
+
+// EnvName returns the environment variable that overrides key.
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
+
+// Set parses value into the setting named key, as written in a flag or
+// environment variable.
+func (s *Settings) Set(key, value string) error {
+	field, ok := s.field(key)
+	if !ok {
+		return fmt.Errorf("unknown setting %q", key)
+	}
+
+	switch field.Interface().(type) {
+	case Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(Duration(d)))
+	case output.Format:
+		format, err := output.ParseFormat(value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(format))
+	case string:
+		field.SetString(value)
+	case bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(n))
+	default:
+		return fmt.Errorf("setting %q has unsupported type %s", key, field.Type())
+	}
+	return nil
+}
+
+func (s *Settings) field(key string) (reflect.Value, bool) {
+	v := reflect.ValueOf(s).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).Tag.Get("json") == key {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// Validate reports settings that cannot be used, whichever source they
+// came from.
+func (s *Settings) Validate() error {
+	if _, err := output.ParseFormat(string(s.Output)); err != nil {
+		return err
+	}
+	if s.CacheInterval <= 0 {
+		return fmt.Errorf("cache-interval must be positive, got %s", time.Duration(s.CacheInterval))
+	}
+	return nil
+}
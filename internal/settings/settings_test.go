@@ -0,0 +1,148 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/roninii/pokedexcli/internal/output"
+)
+
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadMissing(t *testing.T) {
+	s, err := Load(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatalf("Loading a missing file: %v", err)
+	}
+	if s != Default() {
+		t.Errorf("Expected defaults, got %+v", s)
+	}
+}
+
+func TestPrecedence(t *testing.T) {
+	path := writeConfig(t, `{
+		"prompt": "file> ",
+		"output": "json",
+		"cache-interval": "1m",
+		"retries": 5
+	}`)
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Loading: %v", err)
+	}
+	env := map[string]string{
+		"POKEDEXCLI_PROMPT":  "env> ",
+		"POKEDEXCLI_OFFLINE": "true",
+	}
+	if err := s.ApplyEnv(func(key string) string { return env[key] }); err != nil {
+		t.Fatalf("Applying environment: %v", err)
+	}
+	if err := s.Set("retries", "7"); err != nil {
+		t.Fatalf("Setting retries: %v", err)
+	}
+
+	if s.Prompt != "env> " {
+		t.Errorf("Expected the environment to override the file's prompt, got %q", s.Prompt)
+	}
+	if s.Output != output.JSON {
+		t.Errorf("Expected output from the file, got %q", s.Output)
+	}
+	if time.Duration(s.CacheInterval) != time.Minute {
+		t.Errorf("Expected a one minute cache interval, got %v", time.Duration(s.CacheInterval))
+	}
+	if !s.Offline {
+		t.Error("Expected offline to be set from the environment")
+	}
+	if s.Retries != 7 {
+		t.Errorf("Expected the flag to override retries, got %d", s.Retries)
+	}
+	if s.BaseURL != Default().BaseURL {
+		t.Errorf("Expected the default base URL, got %q", s.BaseURL)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	for _, contents := range []string{
+		`{"promt": "typo> "}`,
+		`{"cache-interval": 5}`,
+		`{"retries": "many"}`,
+	} {
+		if _, err := Load(writeConfig(t, contents)); err == nil {
+			t.Errorf("Expected an error loading %s", contents)
+		}
+	}
+
+	s := Default()
+	for key, value := range map[string]string{
+		"retries":     "many",
+		"offline":     "maybe",
+		"retry-delay": "soon",
+		"output":      "yaml",
+		"colour":      "blue",
+	} {
+		if err := s.Set(key, value); err == nil {
+			t.Errorf("Expected an error setting %s to %q", key, value)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if s := Default(); s.Validate() != nil {
+		t.Errorf("Expected the defaults to be valid, got %v", s.Validate())
+	}
+
+	cases := []struct {
+		name   string
+		config string
+		env    map[string]string
+		key    string
+		value  string
+	}{
+		{name: "file output", config: `{"output": "yaml"}`},
+		{name: "file interval", config: `{"cache-interval": "0s"}`},
+		{name: "env interval", config: `{}`, env: map[string]string{"POKEDEXCLI_CACHE_INTERVAL": "0s"}},
+		{name: "flag interval", config: `{}`, key: "cache-interval", value: "0"},
+		{name: "negative interval", config: `{}`, key: "cache-interval", value: "-5s"},
+	}
+
+	for _, c := range cases {
+		s, err := Load(writeConfig(t, c.config))
+		if err != nil {
+			t.Fatalf("%s: loading: %v", c.name, err)
+		}
+		if err := s.ApplyEnv(func(key string) string { return c.env[key] }); err != nil {
+			t.Fatalf("%s: applying environment: %v", c.name, err)
+		}
+		if c.key != "" {
+			if err := s.Set(c.key, c.value); err != nil {
+				t.Fatalf("%s: setting %s: %v", c.name, c.key, err)
+			}
+		}
+
+		if err := s.Validate(); err == nil {
+			t.Errorf("%s: expected the settings to be rejected", c.name)
+		}
+	}
+}
+
+func TestKeys(t *testing.T) {
+	s := Default()
+	for _, key := range Keys() {
+		if _, ok := s.field(key); !ok || key == "" {
+			t.Errorf("Key %q does not name a setting", key)
+		}
+	}
+	if name := EnvName("disk-cache-ttl"); name != "POKEDEXCLI_DISK_CACHE_TTL" {
+		t.Errorf("Unexpected environment variable %s", name)
+	}
+}
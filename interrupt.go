@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// interrupter turns SIGINT into cancellation of the running command's
+// context, so Ctrl-C aborts a slow request instead of the whole session.
+// While the REPL is idle at the prompt it simply redraws the prompt; when
+// running non-interactively there is no prompt to return to, so it exits.
+type interrupter struct {
+	mu          sync.Mutex
+	cancel      context.CancelFunc
+	interactive bool
+	prompt      string
+}
+
+func (i *interrupter) listen() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt)
+
+	for range sigs {
+		i.mu.Lock()
+		switch {
+		case i.cancel != nil:
+			i.cancel()
+		case i.interactive:
+			fmt.Print("\n" + i.prompt)
+		default:
+			os.Exit(exitInterrupted)
+		}
+		i.mu.Unlock()
+	}
+}
+
+// start returns a context for the next command, cancelled on SIGINT until
+// stop is called.
+func (i *interrupter) start() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	i.mu.Lock()
+	i.cancel = cancel
+	i.mu.Unlock()
+
+	return ctx
+}
+
+func (i *interrupter) stop() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.cancel != nil {
+		i.cancel()
+		i.cancel = nil
+	}
+}
@@ -1,33 +1,247 @@
 package main
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"path/filepath"
+	"slices"
+	"time"
 
+	"github.com/roninii/pokedexcli/internal/alias"
+	"github.com/roninii/pokedexcli/internal/cassette"
 	pokecmd "github.com/roninii/pokedexcli/internal/commands"
+	"github.com/roninii/pokedexcli/internal/history"
+	"github.com/roninii/pokedexcli/internal/paths"
+	"github.com/roninii/pokedexcli/internal/pokeapi"
+	"github.com/roninii/pokedexcli/internal/pokecache"
+	"github.com/roninii/pokedexcli/internal/pokedex"
+	"github.com/roninii/pokedexcli/internal/settings"
 )
 
 func main() {
-	config := &pokecmd.Config{}
-	scanner := bufio.NewScanner(os.Stdin)
+	defaults := settings.Default()
+	flag.String("base-url", defaults.BaseURL, "PokeAPI base URL")
+	flag.String("prompt", defaults.Prompt, "prompt shown by the interactive shell")
+	flag.Int("retries", defaults.Retries, "maximum attempts for each PokeAPI request")
+	flag.Duration("retry-delay", time.Duration(defaults.RetryDelay), "initial delay between PokeAPI retries")
+	flag.Duration("cache-interval", time.Duration(defaults.CacheInterval), "how long responses stay in the in-memory cache")
+	flag.Int("cache-max-bytes", defaults.CacheMaxBytes, "maximum size of the in-memory response cache in bytes (0 for no limit)")
+	flag.Int("cache-max-entries", defaults.CacheMaxEntries, "maximum number of responses kept in memory (0 for no limit)")
+	flag.String("disk-cache-dir", "", "directory for the persistent response cache (default under the user cache directory)")
+	flag.Int("disk-cache-ttl", defaults.DiskCacheTTL, "days to keep responses in the persistent cache (0 disables it)")
+	flag.Bool("offline", false, "serve PokeAPI requests from a local snapshot instead of the network")
+	flag.String("snapshot-dir", "", "directory holding the offline snapshot (default under the XDG data directory)")
+	flag.String("output", string(defaults.Output), "output format for commands: text or json")
+	flag.String("history-file", "", "file to record entered commands in (default under the XDG data directory)")
+	flag.String("aliases-file", "", "file holding user-defined aliases and macros (default under the user config directory)")
+	flag.String("save", "", "path to the Pokedex save file (default under the XDG data directory)")
+	configPath := flag.String("config", "", "settings file (default config.json under the user config directory, or $"+settings.EnvPrefix+"CONFIG)")
+	cassettePath := flag.String("cassette", "", "record PokeAPI traffic to, or replay it from, this file, bypassing the response caches")
+	cassetteMode := flag.String("cassette-mode", "replay", "whether -cassette should \"record\" or \"replay\"")
+	keepGoing := flag.Bool("keep-going", false, "keep running a script after a command fails")
+	flag.Parse()
 
-	for {
-		fmt.Print("Pokedex > ")
-		scanner.Scan()
-		input := scanner.Text()
-		cleanInput := pokecmd.CleanInput(input)
-		firstWord := cleanInput[0]
+	cfg, err := loadSettings(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading settings: %v\n", err)
+		os.Exit(exitUsage)
+	}
+
+	if cfg.SavePath == "" {
+		path, err := pokedex.DefaultSavePath()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error locating save file: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.SavePath = path
+	}
+	if err := pokedex.Load(cfg.SavePath); err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading the Pokedex: %v\n", err)
+		os.Exit(1)
+	}
 
-		command, ok := pokecmd.Commands[firstWord]
-		if !ok {
-			fmt.Println("Unknown command")
-			continue
+	if cfg.HistoryFile == "" {
+		path, err := history.DefaultPath()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error locating history file: %v\n", err)
+			os.Exit(1)
 		}
+		cfg.HistoryFile = path
+	}
+	commandHistory, err := history.Load(cfg.HistoryFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading history: %v\n", err)
+		os.Exit(1)
+	}
+
+	if cfg.AliasesFile == "" {
+		path, err := alias.DefaultPath()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error locating aliases file: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.AliasesFile = path
+	}
+	aliases, err := alias.Load(cfg.AliasesFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading aliases: %v\n", err)
+		os.Exit(1)
+	}
+
+	// A cassette has to see every request, both to record a complete session
+	// and so that replaying one does not depend on what is cached locally.
+	var responses pokeapi.Cache
+	if *cassettePath == "" {
+		responses = newResponseCache(cfg)
+	}
+
+	if cfg.SnapshotDir == "" {
+		dataDir, err := paths.DataDir()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error locating snapshot directory: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.SnapshotDir = filepath.Join(dataDir, "snapshot")
+	}
+
+	var transport http.RoundTripper = http.DefaultTransport
+	if cfg.Offline {
+		transport = pokeapi.NewSnapshotTransport(cfg.SnapshotDir)
+	}
+	if *cassettePath != "" {
+		mode, err := cassette.ParseMode(*cassetteMode)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(exitUsage)
+		}
+		recorder, err := cassette.New(*cassettePath, mode, transport)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening cassette: %v\n", err)
+			os.Exit(exitFailure)
+		}
+		transport = recorder
+	}
+	httpClient := &http.Client{Transport: transport, Timeout: 10 * time.Second}
+
+	client := pokeapi.NewClient(cfg.BaseURL, httpClient, responses)
+	client.SetRetryPolicy(pokeapi.RetryPolicy{
+		MaxAttempts: cfg.Retries,
+		BaseDelay:   time.Duration(cfg.RetryDelay),
+		MaxDelay:    pokeapi.DefaultRetryPolicy.MaxDelay,
+	})
+
+	config := &pokecmd.Config{
+		Client:   client,
+		Settings: cfg,
+		Stdin:    os.Stdin,
+		Stdout:   os.Stdout,
+		Stderr:   os.Stderr,
+		History:  commandHistory,
+		Aliases:  aliases,
+	}
+	args := flag.Args()
+	interactive := len(args) == 0 && isTerminal(os.Stdin)
+	interrupts := &interrupter{interactive: interactive, prompt: cfg.Prompt}
+	go interrupts.listen()
+
+	switch {
+	case interactive:
+		startRepl(config, interrupts)
+	case len(args) == 0:
+		os.Exit(runScript(config, interrupts, config.Stdin, *keepGoing))
+	case args[0] == "run":
+		os.Exit(runScriptFile(config, interrupts, args[1:], *keepGoing))
+	default:
+		os.Exit(runOneShot(config, interrupts, args))
+	}
+}
+
+// loadSettings reads the config file at path, or the default one, then
+// applies environment variables and finally any flags set on the command
+// line, so each overrides the one before. The result is validated only once
+// all of them have been applied.
+func loadSettings(path string) (settings.Settings, error) {
+	if path == "" {
+		path = os.Getenv(settings.EnvPrefix + "CONFIG")
+	}
+	if path == "" {
+		defaultPath, err := settings.DefaultPath()
+		if err != nil {
+			return settings.Settings{}, err
+		}
+		path = defaultPath
+	}
+
+	cfg, err := settings.Load(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := cfg.ApplyEnv(os.Getenv); err != nil {
+		return cfg, err
+	}
+
+	keys := settings.Keys()
+	flag.Visit(func(f *flag.Flag) {
+		if err == nil && slices.Contains(keys, f.Name) {
+			err = cfg.Set(f.Name, f.Value.String())
+		}
+	})
+	if err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// runScriptFile implements "pokedexcli run [-keep-going] <script>".
+func runScriptFile(config *pokecmd.Config, interrupts *interrupter, args []string, keepGoing bool) int {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	flags.BoolVar(&keepGoing, "keep-going", keepGoing, "continue running the script after a command fails")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: pokedexcli run [-keep-going] <script>")
+		return exitUsage
+	}
+
+	script, err := os.Open(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening script: %v\n", err)
+		return exitFailure
+	}
+	defer script.Close()
+
+	return runScript(config, interrupts, script, keepGoing)
+}
+
+// newResponseCache returns the in-memory cache described by cfg, backed by
+// the persistent cache unless that is disabled or cannot be opened.
+func newResponseCache(cfg settings.Settings) pokeapi.Cache {
+	cache := pokecache.NewBoundedCache(time.Duration(cfg.CacheInterval), pokecache.Limits{
+		MaxEntries: cfg.CacheMaxEntries,
+		MaxBytes:   cfg.CacheMaxBytes,
+	})
+	if cfg.DiskCacheTTL <= 0 {
+		return cache
+	}
+
+	disk, err := openDiskCache(cfg.DiskCacheDir, time.Duration(cfg.DiskCacheTTL)*24*time.Hour)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Persistent cache disabled: %v\n", err)
+		return cache
+	}
+	return pokecache.NewTiered(cache, disk)
+}
 
-		err := command.Callback(config, cleanInput[1:])
+func openDiskCache(dir string, ttl time.Duration) (*pokecache.DiskCache, error) {
+	if dir == "" {
+		defaultDir, err := pokecache.DefaultDiskCacheDir()
 		if err != nil {
-			fmt.Printf("Error executing command: %s; %v\n", command.Name, err)
+			return nil, err
 		}
+		dir = defaultDir
 	}
+	return pokecache.NewDiskCache(dir, ttl)
 }
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	pokecmd "github.com/roninii/pokedexcli/internal/commands"
+	"github.com/roninii/pokedexcli/internal/lineedit"
+	"github.com/roninii/pokedexcli/internal/output"
+)
+
+// Exit statuses for one-shot and scripted runs.
+const (
+	exitOK          = 0
+	exitFailure     = 1
+	exitUsage       = 2
+	exitInterrupted = 130
+)
+
+var errUnknownCommand = errors.New("Unknown command")
+
+// commandError is returned when a command's callback fails, as opposed to
+// the input not naming a valid command in the first place.
+type commandError struct {
+	name string
+	err  error
+}
+
+func (e *commandError) Error() string {
+	return fmt.Sprintf("Error executing command: %s; %v", e.name, e.err)
+}
+
+func (e *commandError) Unwrap() error {
+	return e.err
+}
+
+func exitCode(err error) int {
+	var usageErr *pokecmd.UsageError
+	switch {
+	case err == nil, errors.Is(err, pokecmd.ErrExit):
+		return exitOK
+	case errors.Is(err, context.Canceled):
+		return exitInterrupted
+	case errors.Is(err, errUnknownCommand), errors.As(err, &usageErr):
+		return exitUsage
+	}
+	return exitFailure
+}
+
+func printError(w io.Writer, err error) {
+	if errors.Is(err, context.Canceled) {
+		fmt.Fprintln(w, "\nCancelled.")
+		return
+	}
+	fmt.Fprintln(w, err)
+}
+
+// execute runs the command named by the first of words with the rest as its
+// arguments, after expanding aliases and macros. A macro runs each of its
+// commands in turn, stopping at the first that fails. Empty input is a
+// no-op.
+func execute(config *pokecmd.Config, interrupts *interrupter, words []string) error {
+	commands, err := config.Expand(words)
+	if err != nil {
+		return err
+	}
+	for _, words := range commands {
+		if err := executeCommand(config, interrupts, words); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func executeCommand(config *pokecmd.Config, interrupts *interrupter, words []string) error {
+	if len(words) == 0 {
+		return nil
+	}
+
+	command, ok := pokecmd.Lookup(words[0])
+	if !ok {
+		return errUnknownCommand
+	}
+
+	args, format, err := command.SplitOutputFlag(words[1:], config.Settings.Output)
+	if err != nil {
+		return err
+	}
+	if err := command.ValidateArgs(args); err != nil {
+		return err
+	}
+	config.Out = output.New(config.Stdout, format)
+
+	ctx := interrupts.start()
+	defer interrupts.stop()
+
+	if err := command.Callback(ctx, config, args); err != nil {
+		return &commandError{name: command.Name, err: err}
+	}
+	return nil
+}
+
+// executeLine tokenizes a line of input and executes it.
+func executeLine(config *pokecmd.Config, interrupts *interrupter, line string) error {
+	words, err := pokecmd.Tokenize(line)
+	if err != nil {
+		return fmt.Errorf("Invalid input: %v", err)
+	}
+	return execute(config, interrupts, words)
+}
+
+// recordHistory expands history references such as !! and !3 in line,
+// echoing the result like a shell does, and records the command.
+func recordHistory(config *pokecmd.Config, line string) (string, error) {
+	if config.History == nil {
+		return line, nil
+	}
+
+	line, expanded, err := config.History.Expand(line)
+	if err != nil {
+		return "", err
+	}
+	if expanded {
+		fmt.Fprintln(config.Stdout, line)
+	}
+
+	if err := config.History.Add(line); err != nil {
+		fmt.Fprintf(config.Stderr, "Error saving history: %v\n", err)
+	}
+	return line, nil
+}
+
+// lineReader reads one line of input per prompt.
+type lineReader interface {
+	ReadLine() (string, error)
+}
+
+// scannerReader is the fallback when stdin is not a terminal that supports
+// line editing.
+type scannerReader struct {
+	scanner *bufio.Scanner
+	out     io.Writer
+	prompt  string
+}
+
+func (r *scannerReader) ReadLine() (string, error) {
+	fmt.Fprint(r.out, r.prompt)
+	if !r.scanner.Scan() {
+		fmt.Fprintln(r.out)
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
+}
+
+func newLineReader(config *pokecmd.Config) lineReader {
+	if f, ok := config.Stdin.(*os.File); ok && lineedit.Supported(f) {
+		editor := lineedit.New(f, config.Stdout)
+		editor.Prompt = config.Settings.Prompt
+		editor.Complete = config.Complete
+		if config.History != nil {
+			editor.History = config.History.Entries
+		}
+		return editor
+	}
+
+	return &scannerReader{
+		scanner: bufio.NewScanner(config.Stdin),
+		out:     config.Stdout,
+		prompt:  config.Settings.Prompt,
+	}
+}
+
+func startRepl(config *pokecmd.Config, interrupts *interrupter) {
+	reader := newLineReader(config)
+
+	for {
+		input, err := reader.ReadLine()
+		if errors.Is(err, lineedit.ErrInterrupted) {
+			continue
+		}
+		if err != nil {
+			// Treat Ctrl-D like the exit command so the Pokedex is saved.
+			execute(config, interrupts, []string{"exit"})
+			return
+		}
+
+		line, err := recordHistory(config, input)
+		if err != nil {
+			printError(config.Stdout, err)
+			continue
+		}
+
+		err = executeLine(config, interrupts, line)
+		if errors.Is(err, pokecmd.ErrExit) {
+			return
+		}
+		if err != nil {
+			printError(config.Stdout, err)
+		}
+	}
+}
+
+// runScript executes each line of r as a command without printing prompts.
+// Blank lines and lines starting with # are skipped. It stops at the first
+// failing command unless keepGoing is set, and returns the exit status for
+// the first failure.
+func runScript(config *pokecmd.Config, interrupts *interrupter, r io.Reader, keepGoing bool) int {
+	scanner := bufio.NewScanner(r)
+	status := exitOK
+
+	for line := 1; scanner.Scan(); line++ {
+		input := strings.TrimSpace(scanner.Text())
+		if input == "" || strings.HasPrefix(input, "#") {
+			continue
+		}
+
+		err := executeLine(config, interrupts, input)
+		if errors.Is(err, pokecmd.ErrExit) {
+			return status
+		}
+		if err == nil {
+			continue
+		}
+
+		fmt.Fprintf(config.Stderr, "line %d: ", line)
+		printError(config.Stderr, err)
+		if status == exitOK {
+			status = exitCode(err)
+		}
+		if !keepGoing || errors.Is(err, context.Canceled) {
+			return status
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(config.Stderr, "Error reading script: %v\n", err)
+		return exitFailure
+	}
+	return status
+}
+
+// runOneShot executes a single command given as program arguments and
+// returns its exit status.
+func runOneShot(config *pokecmd.Config, interrupts *interrupter, args []string) int {
+	err := execute(config, interrupts, normalizeArgs(args))
+	if err != nil && !errors.Is(err, pokecmd.ErrExit) {
+		printError(config.Stderr, err)
+	}
+	return exitCode(err)
+}
+
+// normalizeArgs lowercases program arguments much as Tokenize lowercases
+// unquoted text, so "pokedexcli explore Eterna-Forest-Area" behaves like
+// the same line typed at the prompt. The shell has already removed any
+// quotes, so the command decides which of its arguments keep their case;
+// see CliCommand.NormalizeCase. Words after a user-defined alias, which
+// has no declared arguments, are all lowercased unless they hold
+// whitespace.
+func normalizeArgs(args []string) []string {
+	if len(args) == 0 {
+		return args
+	}
+	name := strings.ToLower(args[0])
+	command, _ := pokecmd.Lookup(name)
+	return append([]string{name}, command.NormalizeCase(args[1:])...)
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
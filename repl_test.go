@@ -1,46 +1,166 @@
 package main
 
 import (
+	"bytes"
+	"io"
+	"math/rand"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	pokecmd "github.com/roninii/pokedexcli/internal/commands"
+	"github.com/roninii/pokedexcli/internal/history"
+	"github.com/roninii/pokedexcli/internal/pokeapitest"
+	"github.com/roninii/pokedexcli/internal/pokedex"
+	"github.com/roninii/pokedexcli/internal/settings"
 )
 
-func TestCleanInput(t *testing.T) {
+func TestRunScript(t *testing.T) {
 	cases := []struct {
-		input    string
-		expected []string
+		script    string
+		keepGoing bool
+		expected  int
 	}{
 		{
-			input:    " hello world ",
-			expected: []string{"hello", "world"},
+			script:   "# only comments\n\n   \n",
+			expected: exitOK,
+		},
+		{
+			script:   "catch\nbogus\n",
+			expected: exitUsage,
 		},
 		{
-			input:    "HeLlO WoRlD",
-			expected: []string{"hello", "world"},
+			script:    "bogus\ncatch\n",
+			keepGoing: true,
+			expected:  exitUsage,
 		},
 		{
-			input:    "world",
-			expected: []string{"world"},
+			script:   "mapb\n",
+			expected: exitFailure,
 		},
 		{
-			input:    "",
-			expected: []string{},
+			script:   "exit\nbogus\n",
+			expected: exitOK,
 		},
 	}
 
 	for _, c := range cases {
-		actual := cleanInput(c.input)
+		config := &pokecmd.Config{
+			Settings: settings.Settings{SavePath: filepath.Join(t.TempDir(), "pokedex.json")},
+			Stdout:   io.Discard,
+			Stderr:   io.Discard,
+		}
+		status := runScript(config, &interrupter{}, strings.NewReader(c.script), c.keepGoing)
 
-		if len(actual) != len(c.expected) {
-			t.Errorf("Expected length of %d but got %d", len(c.expected), len(actual))
-			continue
+		if status != c.expected {
+			t.Errorf("Expected status %d for script %q, got %d", c.expected, c.script, status)
 		}
-		for i := range actual {
-			actualWord := actual[i]
-			expectedWord := c.expected[i]
+	}
+}
 
-			if actualWord != expectedWord {
-				t.Errorf("Expected %s but got %s", expectedWord, actualWord)
+func TestReplHistory(t *testing.T) {
+	commandHistory, err := history.Load(filepath.Join(t.TempDir(), "history"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	config := &pokecmd.Config{
+		Settings: settings.Settings{SavePath: filepath.Join(t.TempDir(), "pokedex.json")},
+		Stdin:    strings.NewReader("history\n!!\n!3\n!1 -o json\n"),
+		Stdout:   &out,
+		Stderr:   &out,
+		History:  commandHistory,
+	}
+	startRepl(config, &interrupter{})
+
+	expected := []string{"history", "history", "!3: event not found", "history -o json"}
+	if entries := commandHistory.Entries(); strings.Join(entries[:3], ",") != "history,history,history -o json" {
+		t.Errorf("Unexpected history entries %q", entries)
+	}
+	for _, line := range expected {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("Expected output to contain %q, got:\n%s", line, out.String())
+		}
+	}
+	if !strings.Contains(out.String(), `{"number":2,"command":"history"}`) {
+		t.Errorf("Expected re-run history command to list JSON entries, got:\n%s", out.String())
+	}
+}
+
+// TestOneShotMatchesScript checks that a command given as program arguments
+// is normalised the same way as the same line read from a script.
+func TestOneShotMatchesScript(t *testing.T) {
+	server := pokeapitest.NewServer()
+	defer server.Close()
+
+	newConfig := func(out *bytes.Buffer) *pokecmd.Config {
+		return &pokecmd.Config{
+			Client:   server.NewClient(),
+			Settings: settings.Settings{SavePath: filepath.Join(t.TempDir(), "pokedex.json")},
+			Stdout:   out,
+			Stderr:   out,
+		}
+	}
+
+	cases := []struct {
+		args     []string
+		expected int
+	}{
+		{args: []string{"EXPLORE", "Eterna-Forest-Area"}, expected: exitOK},
+		{args: []string{"explore", "Nowhere"}, expected: exitFailure},
+		{args: []string{"Bogus"}, expected: exitUsage},
+	}
+
+	for _, c := range cases {
+		var oneShot, script bytes.Buffer
+		oneShotStatus := runOneShot(newConfig(&oneShot), &interrupter{}, c.args)
+		scriptStatus := runScript(newConfig(&script), &interrupter{}, strings.NewReader(strings.Join(c.args, " ")+"\n"), false)
+
+		if oneShotStatus != c.expected || scriptStatus != c.expected {
+			t.Errorf("Expected status %d for %q, got %d one-shot and %d from a script", c.expected, c.args, oneShotStatus, scriptStatus)
+		}
+		if got, want := oneShot.String(), strings.TrimPrefix(script.String(), "line 1: "); got != want {
+			t.Errorf("Expected the same output for %q one-shot and from a script\n--- one-shot\n%s\n--- script\n%s", c.args, got, want)
+		}
+	}
+}
+
+func TestOneShotKeepsNickname(t *testing.T) {
+	server := pokeapitest.NewServer()
+	defer server.Close()
+
+	cases := []struct {
+		args     []string
+		nickname string
+	}{
+		{args: []string{"CATCH", "Pidgey", "Sky King"}, nickname: "Sky King"},
+		{args: []string{"c", "pidgey", "Sparky"}, nickname: "Sparky"},
+		{args: []string{"catch", "-O", "JSON", "pidgey", "Sparky"}, nickname: "Sparky"},
+	}
+
+	for _, c := range cases {
+		pokedex.Reset()
+		var out bytes.Buffer
+		config := &pokecmd.Config{
+			Client:   server.NewClient(),
+			Settings: settings.Settings{SavePath: filepath.Join(t.TempDir(), "pokedex.json")},
+			Rand:     rand.New(rand.NewSource(1)),
+			Stdout:   &out,
+			Stderr:   &out,
+		}
+		for i := 0; i < 50 && len(pokedex.Specimens) == 0; i++ {
+			if status := runOneShot(config, &interrupter{}, c.args); status != exitOK {
+				t.Fatalf("Expected %q to succeed, got status %d: %s", c.args, status, out.String())
 			}
 		}
+
+		if len(pokedex.Specimens) != 1 {
+			t.Fatalf("Expected %q to catch a pidgey, got %+v", c.args, pokedex.Specimens)
+		}
+		if s := pokedex.Specimens[0]; s.Species != "pidgey" || s.Nickname != c.nickname {
+			t.Errorf("Expected a pidgey nicknamed %q for %q, got %s nicknamed %q", c.nickname, c.args, s.Species, s.Nickname)
+		}
 	}
+	pokedex.Reset()
 }